@@ -0,0 +1,82 @@
+// Command dbase-gen generates Go structs with dbase tags and typed helpers for every table
+// of a FoxPro database (DBC) or a directory of DBF files.
+//
+// Usage:
+//
+//	dbase-gen -db path/to/database.dbc -pkg models -out models/tables.gen.go
+//	dbase-gen -dir path/to/tables -pkg models -out models/tables.gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+	"github.com/Valentin-Kaiser/go-dbase/gen"
+)
+
+func main() {
+	database := flag.String("db", "", "path of the database (DBC) file")
+	dir := flag.String("dir", "", "path of a directory containing DBF files")
+	pkg := flag.String("pkg", "main", "package name of the generated file")
+	out := flag.String("out", "", "output file, stdout if empty")
+	untested := flag.Bool("untested", false, "open untested file versions")
+	flag.Parse()
+
+	if (len(*database) == 0) == (len(*dir) == 0) {
+		fmt.Fprintln(os.Stderr, "dbase-gen: either -db or -dir has to be set")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config := dbase.Config{
+		Untested:          *untested,
+		ReadOnly:          true,
+		InterpretCodePage: true,
+	}
+	var tables []gen.Table
+	source := *dir
+	if len(*database) > 0 {
+		source = *database
+		config.Filename = *database
+		db, err := dbase.OpenDatabase(&config)
+		if err != nil {
+			fail(err)
+		}
+		tables = gen.FromDatabase(db)
+		err = db.Close()
+		if err != nil {
+			fail(err)
+		}
+	} else {
+		var err error
+		tables, err = gen.FromDirectory(*dir, config)
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	err := gen.Generate(buf, tables, gen.Options{Package: *pkg, Source: source})
+	if err != nil {
+		fail(err)
+	}
+	if len(*out) == 0 {
+		_, err = os.Stdout.Write(buf.Bytes())
+		if err != nil {
+			fail(err)
+		}
+		return
+	}
+	err = os.WriteFile(*out, buf.Bytes(), 0644)
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "dbase-gen: %v\n", dbase.GetErrorTrace(err))
+	os.Exit(1)
+}
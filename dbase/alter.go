@@ -0,0 +1,608 @@
+package dbase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeAction is the kind of change applied to a column by AlterTable
+type ChangeAction int
+
+const (
+	AddAction    ChangeAction = iota // Adds a new column at the end of the table
+	DropAction                       // Removes a column and its data
+	RenameAction                     // Renames a column
+	ModifyAction                     // Changes the type, length, decimals or flags of a column
+)
+
+// ColumnChange describes a change of the table structure applied by AlterTable
+type ColumnChange struct {
+	Action  ChangeAction                           // The kind of change
+	Name    string                                 // Name of the column to drop, rename or modify
+	NewName string                                 // New name of the column (rename only)
+	Column  *Column                                // New column definition (add and modify only)
+	Default interface{}                            // Value of the column in existing rows (add only), blank if nil
+	Convert func(interface{}) (interface{}, error) // Optional conversion of the old value (modify only)
+}
+
+// AddColumn returns a change that adds the column at the end of the table
+func AddColumn(column *Column) ColumnChange {
+	return ColumnChange{Action: AddAction, Column: column}
+}
+
+// DropColumn returns a change that removes the column with the given name
+func DropColumn(name string) ColumnChange {
+	return ColumnChange{Action: DropAction, Name: name}
+}
+
+// RenameColumn returns a change that renames the column with the given name
+func RenameColumn(name string, newName string) ColumnChange {
+	return ColumnChange{Action: RenameAction, Name: name, NewName: newName}
+}
+
+// ModifyColumn returns a change that replaces the definition of the column with the given name.
+// The name of the new column definition is used, so a column can be renamed and retyped at once.
+// Values are converted to the new data type, conversions that would lose data return an error.
+func ModifyColumn(name string, column *Column) ColumnChange {
+	return ColumnChange{Action: ModifyAction, Name: name, Column: column}
+}
+
+// Describes where the data of a column of the altered table comes from
+type alteration struct {
+	column  *Column                                // The new column definition
+	source  int                                    // Position of the column in the current table, -1 for new columns
+	convert bool                                   // Whether the value has to be interpreted and converted
+	fn      func(interface{}) (interface{}, error) // Custom conversion function
+	value   interface{}                            // Default value of new columns
+	mod     *Modification                          // Modification of the source column
+}
+
+// The parts of a file that describe the table layout
+type layout struct {
+	header         *Header
+	columns        []*Column
+	nullFlagColumn *Column
+}
+
+// AlterTable applies the changes to the table structure in the given order.
+// The header and column descriptors are rewritten and every row is migrated to the new layout in place,
+// including the _NullFlags field. Memo addresses of unchanged memo columns are kept.
+// Values of modified columns are converted to the new data type.
+// The file must not be used by other goroutines while the table is altered.
+// If an error occurs while the rows are migrated the table may be left inconsistent, so make a backup first.
+func (file *File) AlterTable(changes ...ColumnChange) error {
+	if file.config.ReadOnly {
+		return newError("dbase-alter-altertable-1", errors.New("table is opened read-only"))
+	}
+	if file.level7() {
+		return newError("dbase-alter-altertable-8", errors.New("altering dBase level 7 tables is not supported"))
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	alterations, err := file.planAlterations(changes)
+	if err != nil {
+		return newError("dbase-alter-altertable-2", err)
+	}
+	old := layout{
+		header:         file.header,
+		columns:        file.table.columns,
+		nullFlagColumn: file.nullFlagColumn,
+	}
+	altered, err := file.newLayout(alterations)
+	if err != nil {
+		return newError("dbase-alter-altertable-3", err)
+	}
+	debugf("Altering table %v - first row: %v => %v - row length: %v => %v", file.config.Filename, old.header.FirstRow, altered.header.FirstRow, old.header.RowLength, altered.header.RowLength)
+	err = file.migrateRows(alterations, old, altered)
+	if err != nil {
+		file.useLayout(old)
+		return newError("dbase-alter-altertable-4", err)
+	}
+	file.useLayout(altered)
+	file.table.projection = nil
+	file.table.mods = make([]*Modification, len(alterations))
+	for i, a := range alterations {
+		file.table.mods[i] = a.mod
+	}
+	err = file.WriteHeader()
+	if err != nil {
+		return newError("dbase-alter-altertable-5", err)
+	}
+	err = file.WriteColumns()
+	if err != nil {
+		return newError("dbase-alter-altertable-6", err)
+	}
+	// Remove the data behind the last row if the table shrunk
+	size := int64(altered.header.FirstRow) + int64(altered.header.RowsCount)*int64(altered.header.RowLength)
+	if size < int64(old.header.FirstRow)+int64(old.header.RowsCount)*int64(old.header.RowLength) {
+		if handle, ok := file.handle.(interface{ Truncate(size int64) error }); ok {
+			err = handle.Truncate(size)
+			if err != nil {
+				return newError("dbase-alter-altertable-7", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Applies the changes to the current columns and returns the resulting column list
+func (file *File) planAlterations(changes []ColumnChange) ([]*alteration, error) {
+	alterations := make([]*alteration, 0, len(file.table.columns))
+	for i, column := range file.table.columns {
+		c := *column
+		a := &alteration{column: &c, source: i}
+		if i < len(file.table.mods) {
+			a.mod = file.table.mods[i]
+		}
+		alterations = append(alterations, a)
+	}
+	find := func(name string) int {
+		for i, a := range alterations {
+			if a.column.Name() == strings.ToUpper(name) {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, change := range changes {
+		switch change.Action {
+		case AddAction:
+			if change.Column == nil {
+				return nil, newError("dbase-alter-planalterations-1", errors.New("no column defined to add"))
+			}
+			if find(change.Column.Name()) >= 0 {
+				return nil, newError("dbase-alter-planalterations-2", fmt.Errorf("column '%s' already exists", change.Column.Name()))
+			}
+			if DataType(change.Column.DataType).level7() {
+				return nil, newError("dbase-alter-planalterations-12", fmt.Errorf("the dBase level 7 type %v of column '%s' is not supported", DataType(change.Column.DataType), change.Column.Name()))
+			}
+			c := *change.Column
+			alterations = append(alterations, &alteration{column: &c, source: -1, value: change.Default})
+		case DropAction:
+			pos := find(change.Name)
+			if pos < 0 {
+				return nil, newError("dbase-alter-planalterations-3", fmt.Errorf("column '%s' not found", change.Name))
+			}
+			alterations = append(alterations[:pos], alterations[pos+1:]...)
+		case RenameAction:
+			pos := find(change.Name)
+			if pos < 0 {
+				return nil, newError("dbase-alter-planalterations-4", fmt.Errorf("column '%s' not found", change.Name))
+			}
+			if len(change.NewName) == 0 || len(change.NewName) > 10 {
+				return nil, newError("dbase-alter-planalterations-5", fmt.Errorf("invalid column name '%s', the name must have 1 to 10 characters", change.NewName))
+			}
+			if other := find(change.NewName); other >= 0 && other != pos {
+				return nil, newError("dbase-alter-planalterations-6", fmt.Errorf("column '%s' already exists", change.NewName))
+			}
+			alterations[pos].column.FieldName = [11]byte{}
+			copy(alterations[pos].column.FieldName[:], strings.ToUpper(change.NewName))
+		case ModifyAction:
+			pos := find(change.Name)
+			if pos < 0 {
+				return nil, newError("dbase-alter-planalterations-7", fmt.Errorf("column '%s' not found", change.Name))
+			}
+			if change.Column == nil {
+				return nil, newError("dbase-alter-planalterations-8", fmt.Errorf("no column definition for column '%s'", change.Name))
+			}
+			if other := find(change.Column.Name()); other >= 0 && other != pos {
+				return nil, newError("dbase-alter-planalterations-9", fmt.Errorf("column '%s' already exists", change.Column.Name()))
+			}
+			if DataType(change.Column.DataType).level7() {
+				return nil, newError("dbase-alter-planalterations-13", fmt.Errorf("the dBase level 7 type %v of column '%s' is not supported", DataType(change.Column.DataType), change.Column.Name()))
+			}
+			c := *change.Column
+			a := alterations[pos]
+			current := a.column
+			a.column = &c
+			// Keep the autoincrement state of the column
+			if current.Flag&byte(AutoincrementFlag) == byte(AutoincrementFlag) && c.Flag&byte(AutoincrementFlag) == byte(AutoincrementFlag) {
+				c.Next = current.Next
+				c.Step = current.Step
+			}
+			if current.DataType != c.DataType || current.Length != c.Length || current.Decimals != c.Decimals || current.Flag != c.Flag || change.Convert != nil {
+				a.convert = true
+				if change.Convert != nil {
+					a.fn = change.Convert
+				}
+			}
+		default:
+			return nil, newError("dbase-alter-planalterations-10", fmt.Errorf("invalid change action %v", change.Action))
+		}
+	}
+	if len(alterations) == 0 {
+		return nil, newError("dbase-alter-planalterations-11", errors.New("a table needs at least one column"))
+	}
+	return alterations, nil
+}
+
+// Calculates the header, column positions and null flag column of the altered table
+func (file *File) newLayout(alterations []*alteration) (layout, error) {
+	header := *file.header
+	header.RowLength = 1
+	columns := make([]*Column, 0, len(alterations))
+	nullFlagLength := 0
+	for _, a := range alterations {
+		column := a.column
+		if DataType(column.DataType).memo() {
+			if file.memoHeader == nil {
+				return layout{}, newError("dbase-alter-newlayout-1", fmt.Errorf("%w: memo column '%s' needs a memo file", ErrNoFPT, column.Name()))
+			}
+		}
+		if column.DataType == byte(Varchar) || column.DataType == byte(Varbinary) {
+			if column.Flag == byte(NullableFlag) || column.Flag == byte(NullableFlag|BinaryFlag) {
+				nullFlagLength += 2
+			} else {
+				nullFlagLength++
+			}
+		}
+		column.Position = uint32(header.RowLength)
+		header.RowLength += uint16(column.Length)
+		columns = append(columns, column)
+	}
+	var nullFlagColumn *Column
+	if nullFlagLength > 0 {
+		length := nullFlagLength / 8
+		if nullFlagLength%8 > 0 {
+			length++
+		}
+		nullFlagColumn = &Column{
+			FieldName: [11]byte{0x5F, 0x4E, 0x75, 0x6C, 0x6C, 0x46, 0x6C, 0x61, 0x67, 0x73},
+			DataType:  0x30,
+			Position:  uint32(header.RowLength),
+			Length:    uint8(length),
+			Flag:      0x05,
+		}
+		header.RowLength += uint16(length)
+	}
+	descriptors := len(columns)
+	if nullFlagColumn != nil {
+		descriptors++
+	}
+	header.FirstRow = file.firstRow(descriptors)
+	return layout{header: &header, columns: columns, nullFlagColumn: nullFlagColumn}, nil
+}
+
+// Sets the header and columns used to read and write rows
+func (file *File) useLayout(l layout) {
+	file.header = l.header
+	file.table.columns = l.columns
+	file.nullFlagColumn = l.nullFlagColumn
+}
+
+// Rewrites every row from the old to the new layout.
+// Rows are moved towards the end of the file in reverse order and towards the beginning in order,
+// so no row is overwritten before it has been read. If both happens all rows are read first.
+func (file *File) migrateRows(alterations []*alteration, old, altered layout) error {
+	rows := old.header.RowsCount
+	if rows == 0 {
+		return nil
+	}
+	grows := altered.header.FirstRow >= old.header.FirstRow && altered.header.RowLength >= old.header.RowLength
+	shrinks := altered.header.FirstRow <= old.header.FirstRow && altered.header.RowLength <= old.header.RowLength
+	if grows || shrinks {
+		for i := uint32(0); i < rows; i++ {
+			position := i
+			if grows {
+				position = rows - 1 - i
+			}
+			file.useLayout(old)
+			data, err := file.ReadRow(position)
+			if err != nil {
+				return newError("dbase-alter-migraterows-1", err)
+			}
+			err = file.migrateRow(alterations, old, altered, data, position)
+			if err != nil {
+				return newError("dbase-alter-migraterows-2", err)
+			}
+		}
+		return nil
+	}
+	debugf("Reading all rows before migrating to the new layout")
+	file.useLayout(old)
+	data := make([][]byte, rows)
+	for i := uint32(0); i < rows; i++ {
+		raw, err := file.ReadRow(i)
+		if err != nil {
+			return newError("dbase-alter-migraterows-3", err)
+		}
+		data[i] = raw
+	}
+	for i := uint32(0); i < rows; i++ {
+		err := file.migrateRow(alterations, old, altered, data[i], i)
+		if err != nil {
+			return newError("dbase-alter-migraterows-4", err)
+		}
+	}
+	return nil
+}
+
+// Converts the raw data of one row from the old layout and writes it in the new layout
+func (file *File) migrateRow(alterations []*alteration, old, altered layout, data []byte, position uint32) error {
+	file.useLayout(old)
+	// Nothing is interpreted unless needed, memo fields are not read if they are kept
+	current, err := file.bytesToRow(data, position, map[int]bool{})
+	if err != nil {
+		return newError("dbase-alter-migraterow-1", err)
+	}
+	row := &Row{
+		handle:   file,
+		Position: position,
+		Deleted:  current.Deleted,
+		fields:   make([]*Field, 0, len(alterations)),
+	}
+	for _, a := range alterations {
+		field := &Field{column: a.column}
+		if a.source < 0 {
+			if a.value != nil {
+				field.value, err = file.convertValue(a.value, a.column, a.column)
+				if err != nil {
+					return newError("dbase-alter-migraterow-2", err)
+				}
+			} else {
+				field.value, field.raw = blankValue(a.column)
+			}
+			row.fields = append(row.fields, field)
+			continue
+		}
+		source := current.fields[a.source]
+		isVar := source.column.DataType == byte(Varchar) || source.column.DataType == byte(Varbinary)
+		if !a.convert && !isVar {
+			// The raw data is copied as is, memo addresses stay valid
+			field.raw = source.raw
+			row.fields = append(row.fields, field)
+			continue
+		}
+		value, err := source.Value()
+		if err != nil {
+			return newError("dbase-alter-migraterow-3", fmt.Errorf("interpreting column %v of row %v failed with error: %w", source.Name(), position, err))
+		}
+		if a.convert {
+			if a.fn != nil {
+				value, err = a.fn(value)
+			} else {
+				value, err = file.convertValue(value, source.column, a.column)
+			}
+			if err != nil {
+				return newError("dbase-alter-migraterow-4", fmt.Errorf("converting column %v of row %v failed with error: %w", source.Name(), position, err))
+			}
+		}
+		field.value = value
+		row.fields = append(row.fields, field)
+	}
+	file.useLayout(altered)
+	err = file.WriteRow(row)
+	if err != nil {
+		return newError("dbase-alter-migraterow-5", err)
+	}
+	return nil
+}
+
+// Returns the value or raw data of an empty field of the column
+func blankValue(column *Column) (interface{}, []byte) {
+	switch DataType(column.DataType) {
+	case Varchar:
+		return "", nil
+	case Varbinary:
+		return []byte{}, nil
+	case Character, Date, Numeric, Float, Logical:
+		return nil, []byte(strings.Repeat(" ", int(column.Length)))
+	default:
+		return nil, make([]byte, column.Length)
+	}
+}
+
+// Converts a value read from a column to a value that can be written to the target column of the file.
+// Conversions that would lose data (overflow, truncated text, fractional integers) return an error.
+func (file *File) convertValue(value interface{}, from, to *Column) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	// Character values are padded with spaces
+	if s, ok := value.(string); ok && DataType(from.DataType) == Character {
+		value = strings.TrimRight(s, " ")
+	}
+	switch DataType(to.DataType) {
+	case Character, Varchar:
+		s, err := valueToString(value)
+		if err != nil {
+			return nil, newError("dbase-alter-convertvalue-1", err)
+		}
+		// Character values are stored in the encoding of the table, Varchar values as is
+		length := len(s)
+		if DataType(to.DataType) == Character {
+			encoded, _, err := fromUtf8String([]byte(s), file.config.Converter)
+			if err == nil {
+				length = len(encoded)
+			}
+		}
+		if length > int(to.Length) {
+			return nil, newError("dbase-alter-convertvalue-2", fmt.Errorf("value %q exceeds the column length %v", s, to.Length))
+		}
+		return s, nil
+	case Memo:
+		if b, ok := value.([]byte); ok {
+			return b, nil
+		}
+		s, err := valueToString(value)
+		if err != nil {
+			return nil, newError("dbase-alter-convertvalue-3", err)
+		}
+		return s, nil
+	case Varbinary, Blob, General, Picture, NullFlags:
+		var b []byte
+		switch v := value.(type) {
+		case []byte:
+			b = v
+		case string:
+			b = []byte(v)
+		case OLEObject:
+			b = v.Bytes()
+		default:
+			return nil, newError("dbase-alter-convertvalue-4", fmt.Errorf("can not convert %T to %v", value, DataType(to.DataType)))
+		}
+		if DataType(to.DataType) == Varbinary && len(b) > int(to.Length) {
+			return nil, newError("dbase-alter-convertvalue-5", fmt.Errorf("value exceeds the column length %v", to.Length))
+		}
+		return b, nil
+	case Integer, Autoincrement:
+		if d, ok := value.(Decimal); ok {
+			i, ok := d.Int64()
+			if !ok || i < math.MinInt32 || i > math.MaxInt32 {
+				return nil, newError("dbase-alter-convertvalue-16", fmt.Errorf("value %v does not fit into an integer column", d))
+			}
+			return int32(i), nil
+		}
+		f, err := valueToFloat(value)
+		if err != nil {
+			return nil, newError("dbase-alter-convertvalue-6", err)
+		}
+		if f != math.Trunc(f) || f < math.MinInt32 || f > math.MaxInt32 {
+			return nil, newError("dbase-alter-convertvalue-7", fmt.Errorf("value %v does not fit into an integer column", f))
+		}
+		return int32(f), nil
+	case Numeric, Float:
+		if d, ok := value.(Decimal); ok {
+			_, err := d.format(to)
+			if err != nil {
+				return nil, newError("dbase-alter-convertvalue-17", err)
+			}
+			d = d.Round(to.Decimals)
+			if DataType(to.DataType) == Numeric && to.Decimals == 0 {
+				i, ok := d.Int64()
+				if !ok {
+					return nil, newError("dbase-alter-convertvalue-18", fmt.Errorf("value %v does not fit into an int64", d))
+				}
+				return i, nil
+			}
+			return d, nil
+		}
+		f, err := valueToFloat(value)
+		if err != nil {
+			return nil, newError("dbase-alter-convertvalue-8", err)
+		}
+		pow := math.Pow10(int(to.Decimals))
+		f = math.Round(f*pow) / pow
+		s := strconv.FormatFloat(f, 'f', -1, 64)
+		if len(s) > int(to.Length) {
+			return nil, newError("dbase-alter-convertvalue-9", fmt.Errorf("value %v exceeds the column length %v", s, to.Length))
+		}
+		if DataType(to.DataType) == Numeric && to.Decimals == 0 {
+			return int64(f), nil
+		}
+		return f, nil
+	case Double, DBaseDouble, Currency:
+		if d, ok := value.(Decimal); ok && DataType(to.DataType) == Currency {
+			return d.Round(4), nil
+		}
+		f, err := valueToFloat(value)
+		if err != nil {
+			return nil, newError("dbase-alter-convertvalue-10", err)
+		}
+		return f, nil
+	case Logical:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			switch strings.ToUpper(strings.TrimSpace(v)) {
+			case "T", "Y", "TRUE", "YES", "1":
+				return true, nil
+			case "", "F", "N", "FALSE", "NO", "0":
+				return false, nil
+			}
+			return nil, newError("dbase-alter-convertvalue-11", fmt.Errorf("can not convert %q to a logical value", v))
+		}
+		f, err := valueToFloat(value)
+		if err != nil {
+			return nil, newError("dbase-alter-convertvalue-12", err)
+		}
+		return f != 0, nil
+	case Date, DateTime, Timestamp:
+		switch v := value.(type) {
+		case time.Time:
+			if DataType(to.DataType) == Date {
+				return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, v.Location()), nil
+			}
+			return v, nil
+		case string:
+			v = strings.TrimSpace(v)
+			if len(v) == 0 {
+				return nil, nil
+			}
+			for _, layout := range []string{time.RFC3339, "20060102", "2006-01-02"} {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t, nil
+				}
+			}
+			return nil, newError("dbase-alter-convertvalue-13", fmt.Errorf("can not parse %q as date", v))
+		}
+		return nil, newError("dbase-alter-convertvalue-14", fmt.Errorf("can not convert %T to %v", value, DataType(to.DataType)))
+	default:
+		return nil, newError("dbase-alter-convertvalue-15", fmt.Errorf("unsupported column data type: %s", string(to.DataType)))
+	}
+}
+
+// Formats a column value as string
+func valueToString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case Decimal:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "T", nil
+		}
+		return "F", nil
+	case time.Time:
+		if v.IsZero() {
+			return "", nil
+		}
+		return v.Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("can not convert %T to string", value)
+}
+
+// Returns a column value as float64
+func valueToFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case Decimal:
+		return v.Float64(), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		v = strings.TrimSpace(v)
+		if len(v) == 0 {
+			return 0, nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("can not convert %q to a number: %w", v, err)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("can not convert %T to a number", value)
+}
@@ -0,0 +1,129 @@
+package dbase
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Changes the working directory to a temporary directory for the test, as New creates the file with an upper case path
+func chdirTemp(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// Returns the config of the test tables
+func testConfig() *Config {
+	return &Config{Filename: "TEST.DBF", Converter: NewDefaultConverter(charmap.Windows1252), TrimSpaces: true, Untested: true}
+}
+
+// Creates a Windows-1252 table with the columns and rows in a temporary working directory
+func createTestTable(t *testing.T, version FileVersion, columns []*Column, rows []map[string]interface{}) *File {
+	chdirTemp(t)
+	file, err := New(version, testConfig(), columns, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	for _, values := range rows {
+		row, err := file.RowFromMap(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = row.Add()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return file
+}
+
+func mustColumn(t *testing.T, name string, dataType DataType, length uint8, decimals uint8) *Column {
+	column, err := NewColumn(name, dataType, length, decimals, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return column
+}
+
+// Returns the rows of the table as maps
+func readTestTable(t *testing.T, file *File) []map[string]interface{} {
+	err := file.GoTo(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := file.Rows(false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maps := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		m, err := row.ToMap()
+		if err != nil {
+			t.Fatal(err)
+		}
+		maps = append(maps, m)
+	}
+	return maps
+}
+
+func TestAlterTable(t *testing.T) {
+	// Größenwahn has 12 bytes in UTF-8 and fills the column in Windows-1252
+	file := createTestTable(t, FoxPro, []*Column{
+		mustColumn(t, "NAME", Character, 10, 0),
+		mustColumn(t, "QTY", Numeric, 5, 0),
+	}, []map[string]interface{}{
+		{"NAME": "Größenwahn", "QTY": int64(1)},
+		{"NAME": "abc", "QTY": int64(2)},
+		{"NAME": "Ärger", "QTY": int64(3)},
+	})
+	// Growing rows are migrated in reverse order
+	err := file.AlterTable(
+		AddColumn(mustColumn(t, "NOTE", Character, 20, 0)),
+		ModifyColumn("NAME", mustColumn(t, "NAME", Character, 12, 0)),
+	)
+	if err != nil {
+		t.Fatalf("growing the table failed with error: %v", err)
+	}
+	expected := []map[string]interface{}{
+		{"NAME": "Größenwahn", "QTY": int64(1), "NOTE": ""},
+		{"NAME": "abc", "QTY": int64(2), "NOTE": ""},
+		{"NAME": "Ärger", "QTY": int64(3), "NOTE": ""},
+	}
+	if rows := readTestTable(t, file); !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("grown table has rows %v, expected %v", rows, expected)
+	}
+	// Shrinking rows are migrated in order
+	err = file.AlterTable(
+		DropColumn("NOTE"),
+		ModifyColumn("NAME", mustColumn(t, "NAME", Character, 10, 0)),
+	)
+	if err != nil {
+		t.Fatalf("shrinking the table failed with error: %v", err)
+	}
+	expected = []map[string]interface{}{
+		{"NAME": "Größenwahn", "QTY": int64(1)},
+		{"NAME": "abc", "QTY": int64(2)},
+		{"NAME": "Ärger", "QTY": int64(3)},
+	}
+	if rows := readTestTable(t, file); !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("shrunk table has rows %v, expected %v", rows, expected)
+	}
+	if file.header.RowLength != 16 {
+		t.Errorf("shrunk table has row length %v, expected 16", file.header.RowLength)
+	}
+	// Values that do not fit into the encoded column length are rejected
+	err = file.AlterTable(ModifyColumn("NAME", mustColumn(t, "NAME", Character, 9, 0)))
+	if err == nil {
+		t.Fatal("narrowing the column below the length of its values succeeded")
+	}
+}
@@ -0,0 +1,251 @@
+package dbase
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Layouts used to parse date strings if no layouts are configured
+var defaultDateLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02", "20060102"}
+
+// Converts the value to the Go type of the column:
+//
+//	Pointers are dereferenced, nil pointers are nil
+//	driver.Valuer values (e.g. sql.NullString) are replaced by their value
+//	Integer kinds, float32 and json.Number are converted to the number type of the column
+//	Strings are parsed as date with the configured date layouts for D and T columns
+//	io.Reader values of memo columns (M, G, P and W) are kept
+//
+// Conversions that would lose data return an error, values of unknown types are returned unchanged.
+func (file *File) coerce(value interface{}, column *Column) (interface{}, error) {
+	// Readers are read when the value is written to the memo file
+	if r, ok := value.(io.Reader); ok && DataType(column.DataType).memo() {
+		return r, nil
+	}
+	value, err := unwrapValue(value)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	original := value
+	if _, ok := value.(json.Number); !ok {
+		value = normalizeValue(value)
+	}
+	switch DataType(column.DataType) {
+	case Character, Varchar, Memo:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		if b, ok := value.([]byte); ok {
+			return b, nil
+		}
+	case Varbinary, Blob, General, Picture, NullFlags:
+		if s, ok := value.(string); ok {
+			return []byte(s), nil
+		}
+		if b, ok := value.([]byte); ok {
+			return b, nil
+		}
+	case Logical:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case Integer, Autoincrement:
+		i, ok, err := coerceInteger(value)
+		if err != nil {
+			return nil, newError("dbase-coerce-coerce-1", err)
+		}
+		if ok {
+			if i < math.MinInt32 || i > math.MaxInt32 {
+				return nil, newError("dbase-coerce-coerce-2", fmt.Errorf("value %v overflows the integer range", original))
+			}
+			return int32(i), nil
+		}
+	case Numeric, Float, Currency:
+		if DataType(column.DataType) == Numeric && column.Decimals == 0 {
+			i, ok, err := coerceInteger(value)
+			if err != nil {
+				return nil, newError("dbase-coerce-coerce-3", err)
+			}
+			if ok {
+				return i, nil
+			}
+			break
+		}
+		if f, ok := value.(float64); ok {
+			return f, nil
+		}
+		d, ok, err := coerceDecimal(value)
+		if err != nil {
+			return nil, newError("dbase-coerce-coerce-4", err)
+		}
+		if ok {
+			return d, nil
+		}
+	case Double, DBaseDouble:
+		f, ok, err := coerceFloat(value)
+		if err != nil {
+			return nil, newError("dbase-coerce-coerce-5", err)
+		}
+		if ok {
+			return f, nil
+		}
+	case Date, DateTime, Timestamp:
+		if t, ok := value.(time.Time); ok {
+			return t, nil
+		}
+		if s, ok := value.(string); ok {
+			layouts := file.config.DateLayouts
+			if len(layouts) == 0 {
+				layouts = defaultDateLayouts
+			}
+			for _, layout := range layouts {
+				if t, err := time.ParseInLocation(layout, s, file.location()); err == nil {
+					return t, nil
+				}
+			}
+			return nil, newError("dbase-coerce-coerce-6", fmt.Errorf("can not parse %q as date with the layouts %q", s, layouts))
+		}
+	}
+	return original, nil
+}
+
+// Dereferences pointers and resolves driver.Valuer values
+func unwrapValue(value interface{}) (interface{}, error) {
+	for value != nil {
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return nil, newError("dbase-coerce-unwrapvalue-1", err)
+			}
+			value = v
+			continue
+		}
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Ptr {
+			return value, nil
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		value = rv.Elem().Interface()
+	}
+	return nil, nil
+}
+
+// Returns if the value is nil, a nil pointer or a null driver.Valuer
+func isNilValue(value interface{}) bool {
+	v, err := unwrapValue(value)
+	return err == nil && v == nil
+}
+
+// Converts values of basic kinds (including named types) to int64, uint64, float64, string, []byte or bool
+func normalizeValue(value interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32:
+		// Use the shortest representation, float32(0.1) should not become 0.10000000149011612
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(rv.Float(), 'g', -1, 32), 64)
+		return f
+	case reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes()
+		}
+	}
+	return value
+}
+
+// Returns the normalized value as int64, numbers with a fraction or out of range return an error
+func coerceInteger(value interface{}) (int64, bool, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, true, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, false, fmt.Errorf("value %v overflows int64", v)
+		}
+		return int64(v), true, nil
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false, fmt.Errorf("value %v can not be converted to an integer without loss", v)
+		}
+		return int64(v), true, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, true, nil
+		}
+		d, err := ParseDecimal(string(v))
+		if err != nil {
+			return 0, false, err
+		}
+		return coerceInteger(d)
+	case Decimal:
+		i, ok := v.Int64()
+		if !ok {
+			return 0, false, fmt.Errorf("value %v can not be converted to an integer without loss", v)
+		}
+		return i, true, nil
+	}
+	return 0, false, nil
+}
+
+// Returns the normalized integer, json.Number or Decimal value as Decimal
+func coerceDecimal(value interface{}) (Decimal, bool, error) {
+	switch v := value.(type) {
+	case int64:
+		return NewDecimal(v, 0), true, nil
+	case uint64:
+		return Decimal{unscaled: new(big.Int).SetUint64(v)}, true, nil
+	case json.Number:
+		d, err := ParseDecimal(string(v))
+		return d, err == nil, err
+	case Decimal:
+		return v, true, nil
+	}
+	return Decimal{}, false, nil
+}
+
+// Returns the normalized value as float64, values that can not be represented exactly return an error
+func coerceFloat(value interface{}) (float64, bool, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, true, nil
+	case int64:
+		if v > 1<<53 || v < -(1<<53) {
+			return 0, false, fmt.Errorf("value %v can not be converted to float64 without loss", v)
+		}
+		return float64(v), true, nil
+	case uint64:
+		if v > 1<<53 {
+			return 0, false, fmt.Errorf("value %v can not be converted to float64 without loss", v)
+		}
+		return float64(v), true, nil
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil, err
+	case Decimal:
+		f := v.Float64()
+		d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+		if err != nil || d.Cmp(v) != 0 {
+			return 0, false, fmt.Errorf("value %v can not be converted to float64 without loss", v)
+		}
+		return f, true, nil
+	}
+	return 0, false, nil
+}
@@ -27,6 +27,8 @@ const (
 	DBaseMemo       FileVersion = 0x8B
 	DBaseSQLMemo    FileVersion = 0xCB
 	FoxPro2Memo     FileVersion = 0xF5
+	DBase7          FileVersion = 0x04
+	DBase7Memo      FileVersion = 0x8C
 )
 
 // Table file extenstions
@@ -100,6 +102,11 @@ const (
 	Picture   DataType = 0x50 // P - Picture (string)
 	Varbinary DataType = 0x51 // Q - Varbinary ([]byte)
 	Varchar   DataType = 0x56 // V - Varchar (string)
+
+	Autoincrement DataType = 0x2B // + - Autoincrement (int32)
+	Timestamp     DataType = 0x40 // @ - Timestamp (time.Time)
+	DBaseDouble   DataType = 0x4F // O - Double (float64)
+	NullFlags     DataType = 0x30 // 0 - _NullFlags ([]byte)
 )
 
 // Returns the type of the column as string
@@ -107,19 +114,29 @@ func (t DataType) String() string {
 	return string(t)
 }
 
+// Returns if the values of the column type are stored in the memo file
+func (t DataType) memo() bool {
+	return t == Memo || t == General || t == Picture || t == Blob
+}
+
+// Returns if the column type only exists in dBase level 7 tables
+func (t DataType) level7() bool {
+	return t == Autoincrement || t == Timestamp || t == DBaseDouble
+}
+
 func (t DataType) Reflect() reflect.Type {
 	switch t {
 	case Character:
 		return reflect.TypeOf("")
-	case Currency, Double, Float, Numeric:
+	case Currency, Double, Float, Numeric, DBaseDouble:
 		return reflect.TypeOf(float64(0))
-	case Date, DateTime:
+	case Date, DateTime, Timestamp:
 		return reflect.TypeOf(time.Time{})
-	case Integer:
+	case Integer, Autoincrement:
 		return reflect.TypeOf(int32(0))
 	case Logical:
 		return reflect.TypeOf(false)
-	case Memo, Blob, Varchar, Varbinary, General, Picture:
+	case Memo, Blob, Varchar, Varbinary, General, Picture, NullFlags:
 		return reflect.TypeOf([]byte{})
 	}
 	return reflect.TypeOf("")
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -13,7 +14,9 @@ import (
 // Convert year, month and day to a julian day number.
 // (Julian day number -> days since 01-01-4712 BC)
 func ymd2jd(y, m, d int) int {
-	return int(float64(2-(y/100)+y/100/4) + float64(d) + (float64(365.25) * float64(y+4716)) + (float64(30.6001) * float64(m+1)) - 1524.5)
+	// Fliegel and Van Flandern, the inverse of jd2ymd
+	a := (m - 14) / 12
+	return (1461*(y+4800+a))/4 + (367*(m-2-12*a))/12 - (3*((y+4900+a)/100))/4 + d - 32075
 }
 
 // Convert julian day number to year, month and day.
@@ -32,50 +35,132 @@ func jd2ymd(date int) (int, int, int) {
 	return y, m, d
 }
 
-// parseDate parses a date string from a byte slice and returns a time.Time
-func parseDate(raw []byte) (time.Time, error) {
+// parseDate parses a date string from a byte slice and returns a time.Time in the location.
+// Blank dates are empty and returned as zero time and true.
+func parseDate(raw []byte, loc *time.Location) (time.Time, bool, error) {
 	raw = sanitizeString(raw)
 	if len(raw) == 0 {
-		return time.Time{}, nil
+		return time.Time{}, true, nil
 	}
-	t, err := time.Parse("20060102", string(raw))
+	t, err := time.ParseInLocation("20060102", string(raw), loc)
 	if err != nil {
-		return t, newError("dbase-interpreter-parsedate-1", err)
+		return t, false, newError("dbase-interpreter-parsedate-1", err)
 	}
-	return t, nil
+	return t, false, nil
 }
 
-// parseDateTIme parses a date and time string from a byte slice and returns a time.Time
-func parseDateTime(raw []byte) time.Time {
-	raw = sanitizeString(raw)
-	if len(raw) != 8 {
-		return time.Time{}
+// parseDateTime parses the julian day and milliseconds since midnight and returns a time.Time in the location.
+// If milliseconds is false the time is rounded to whole seconds like FoxPro does.
+// Blank values and a julian day of 0 are empty and returned as zero time and true.
+func parseDateTime(raw []byte, loc *time.Location, milliseconds bool) (time.Time, bool) {
+	// The value is binary, only blanks and zeros are removed as a whole
+	if len(raw) != 8 || len(sanitizeString(raw)) == 0 {
+		return time.Time{}, true
 	}
 	julDat := int(binary.LittleEndian.Uint32(raw[:4]))
 	mSec := int(binary.LittleEndian.Uint32(raw[4:]))
+	return julianTime(julDat, mSec, loc, milliseconds)
+}
+
+// parseTimestamp parses a dBase level 7 timestamp consisting of the julian day and milliseconds since midnight
+// stored as two sortable big endian longs. Zero values are empty and returned as zero time and true.
+func parseTimestamp(raw []byte, loc *time.Location, milliseconds bool) (time.Time, bool) {
+	if len(raw) != 8 {
+		return time.Time{}, true
+	}
+	julDat, ok := parseSortableInt(raw[:4])
+	if !ok {
+		return time.Time{}, true
+	}
+	mSec, _ := parseSortableInt(raw[4:])
+	return julianTime(int(julDat), int(mSec), loc, milliseconds)
+}
+
+// julianTime returns the time of the julian day and milliseconds since midnight in the location
+func julianTime(julDat int, mSec int, loc *time.Location, milliseconds bool) (time.Time, bool) {
+	if julDat <= 0 {
+		return time.Time{}, true
+	}
 	// Determine year, month, day
 	y, m, d := jd2ymd(julDat)
-	if y < 0 || y > 9999 {
-		return time.Time{}
+	if y < 1 || y > 9999 {
+		return time.Time{}, true
+	}
+	if !milliseconds {
+		mSec = (mSec + 500) / 1000 * 1000
 	}
-	// Calculate whole seconds and use the remainder as nanosecond resolution
-	nSec := mSec / 1000
-	mSec -= (nSec * 1000)
-	// Create time using ymd and nanosecond timestamp
-	return time.Date(y, time.Month(m), d, 0, 0, nSec, mSec*int(time.Millisecond), time.UTC)
+	// Create time using ymd and the milliseconds as nanoseconds, time.Date normalizes the overflow
+	return time.Date(y, time.Month(m), d, 0, 0, 0, mSec*int(time.Millisecond), loc), false
 }
 
-// parseNumericInt parses a string as byte array to int64
+// parseNumericInt parses a string as byte array to int64.
+// Integral values in float notation (e.g. "12." or "1.2E+3" written by dBase IV) are accepted.
 func parseNumericInt(raw []byte) (int64, error) {
 	trimmed := string(sanitizeString(raw))
 	if len(trimmed) == 0 {
 		return int64(0), nil
 	}
 	i, err := strconv.ParseInt(trimmed, 10, 64)
-	if err != nil {
+	if err == nil {
+		return i, nil
+	}
+	f, ferr := strconv.ParseFloat(trimmed, 64)
+	if ferr != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
 		return i, newError("dbase-conversion-parseint-1", err)
 	}
-	return i, nil
+	return int64(f), nil
+}
+
+// isOverflow returns if the numeric value is filled with asterisks.
+// dBase IV and Clipper write asterisks if a value does not fit into the column.
+func isOverflow(raw []byte) bool {
+	trimmed := sanitizeString(raw)
+	return len(trimmed) > 0 && len(bytes.Trim(trimmed, "*")) == 0
+}
+
+// parseSortableInt parses a dBase level 7 long, stored big endian with an inverted sign bit.
+// Returns false if all bytes are zero (empty value).
+func parseSortableInt(raw []byte) (int32, bool) {
+	u := binary.BigEndian.Uint32(raw)
+	if u == 0 {
+		return 0, false
+	}
+	return int32(u ^ 0x80000000), true
+}
+
+// toSortableInt returns the dBase level 7 representation of the long
+func toSortableInt(i int32) []byte {
+	raw := make([]byte, 4)
+	binary.BigEndian.PutUint32(raw, uint32(i)^0x80000000)
+	return raw
+}
+
+// parseSortableDouble parses a dBase level 7 double, stored big endian with the sign bit inverted for
+// positive values and all bits inverted for negative values. Returns false if all bytes are zero (empty value).
+func parseSortableDouble(raw []byte) (float64, bool) {
+	u := binary.BigEndian.Uint64(raw)
+	if u == 0 {
+		return 0, false
+	}
+	if u&(1<<63) != 0 {
+		u ^= 1 << 63
+	} else {
+		u = ^u
+	}
+	return math.Float64frombits(u), true
+}
+
+// toSortableDouble returns the dBase level 7 representation of the double
+func toSortableDouble(f float64) []byte {
+	u := math.Float64bits(f)
+	if u&(1<<63) == 0 {
+		u ^= 1 << 63
+	} else {
+		u = ^u
+	}
+	raw := make([]byte, 8)
+	binary.BigEndian.PutUint64(raw, u)
+	return raw
 }
 
 // parseFloat parses a string as byte array to float64
@@ -100,13 +185,21 @@ func toUTF8String(raw []byte, converter EncodingConverter) (string, error) {
 	return string(utf8), nil
 }
 
-// fromUTF8String converts a UTF8 string to a byte slice using the given converter
-func fromUtf8String(raw []byte, converter EncodingConverter) ([]byte, error) {
-	utf8, err := converter.Encode(raw)
+// fromUTF8String converts a UTF8 string to a byte slice using the given converter.
+// Returns if characters were replaced if the converter implements LossyConverter.
+func fromUtf8String(raw []byte, converter EncodingConverter) ([]byte, bool, error) {
+	if c, ok := converter.(LossyConverter); ok {
+		encoded, lossy, err := c.EncodeLossy(raw)
+		if err != nil {
+			return raw, false, newError("dbase-conversion-fromutf8string-1", err)
+		}
+		return encoded, lossy, nil
+	}
+	encoded, err := converter.Encode(raw)
 	if err != nil {
-		return raw, newError("dbase-conversion-fromutf8string-1", err)
+		return raw, false, newError("dbase-conversion-fromutf8string-2", err)
 	}
-	return utf8, nil
+	return encoded, false, nil
 }
 
 // Convert data to binary representation
@@ -151,7 +244,7 @@ func sanitizeString(raw []byte) []byte {
 
 // nthBit returns the nth bit of a byte slice
 func getNthBit(bytes []byte, n int) bool {
-	if n > len(bytes)*8 {
+	if n >= len(bytes)*8 {
 		return false
 	}
 	byteIndex := n / 8 // byte index
@@ -164,41 +257,59 @@ func setNthBit(b byte, n int) byte {
 	return b
 }
 
-// setStructField sets the field with the key or dbase tag of name of the struct obj to the given value
-func setStructField(tags map[string]string, obj interface{}, name string, value interface{}) error {
-	if fieldName, ok := tags[name]; ok {
-		name = fieldName
-	}
-	structValue := reflect.ValueOf(obj).Elem()
-	structFieldValue := structValue.FieldByName(name)
-	if !structFieldValue.IsValid() {
+// setStructField sets the field of the struct obj to the given value, nil pointers to embedded structs are allocated
+func setStructField(obj interface{}, field structField, value interface{}) error {
+	name := field.field.Name
+	structFieldValue, ok := fieldByIndex(reflect.ValueOf(obj).Elem(), field.index, value != nil)
+	if !ok {
+		// Null value of a field in a nil embedded struct
 		return nil
 	}
 	if !structFieldValue.CanSet() {
 		return newError("dbase-conversion-setstructfield-1", fmt.Errorf("cannot set %s field value", name))
 	}
 	structFieldType := structFieldValue.Type()
+	if value == nil {
+		structFieldValue.Set(reflect.Zero(structFieldType))
+		return nil
+	}
+	// Pointer fields are used for nullable columns
+	if structFieldType.Kind() == reflect.Ptr {
+		value = dynamicCast(value, structFieldType.Elem())
+		val := reflect.ValueOf(value)
+		if structFieldType.Elem() != val.Type() {
+			return newError("dbase-conversion-setstructfield-2", fmt.Errorf("provided value type %v didn't match obj field type %v", val.Type(), structFieldType))
+		}
+		ptr := reflect.New(structFieldType.Elem())
+		ptr.Elem().Set(val)
+		structFieldValue.Set(ptr)
+		return nil
+	}
 	value = dynamicCast(value, structFieldType)
 	val := reflect.ValueOf(value)
 	if structFieldType != val.Type() {
-		return newError("dbase-conversion-setstructfield-2", fmt.Errorf("provided value type %v didn't match obj field type %v", val.Type(), structFieldType))
+		return newError("dbase-conversion-setstructfield-3", fmt.Errorf("provided value type %v didn't match obj field type %v", val.Type(), structFieldType))
 	}
 	structFieldValue.Set(val)
 	return nil
 }
 
-// structTags extracts the dbase tag from the struct fields
-func structTags(v interface{}) map[string]string {
-	tags := make(map[string]string)
-	structValue := reflect.ValueOf(v).Elem()
-	for i := 0; i < structValue.NumField(); i++ {
-		field := structValue.Type().Field(i)
-		tag := field.Tag.Get("dbase")
-		if len(tag) > 0 {
-			tags[tag] = field.Name
+// structTags maps the column names of the dbase tags and the struct field names to the struct fields
+func structTags(v interface{}) (map[string]structField, error) {
+	fields, err := structFields(reflect.TypeOf(v))
+	if err != nil {
+		return nil, newError("dbase-conversion-structtags-1", err)
+	}
+	tags := make(map[string]structField, len(fields))
+	for _, f := range fields {
+		if _, ok := tags[f.field.Name]; !ok {
+			tags[f.field.Name] = f
 		}
 	}
-	return tags
+	for _, f := range fields {
+		tags[f.tag.name] = f
+	}
+	return tags, nil
 }
 
 // dynamicCast casts the given value to the given type if possible
@@ -212,5 +323,14 @@ func dynamicCast(v interface{}, t reflect.Type) interface{} {
 	if reflect.TypeOf(v).ConvertibleTo(t) {
 		return reflect.ValueOf(v).Convert(t).Interface()
 	}
+	// Decimal values can be read into float fields and float values into Decimal fields
+	if d, ok := v.(Decimal); ok && (t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64) {
+		return reflect.ValueOf(d.Float64()).Convert(t).Interface()
+	}
+	if f, ok := v.(float64); ok && t == reflect.TypeOf(Decimal{}) {
+		if d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64)); err == nil {
+			return d
+		}
+	}
 	return v
 }
@@ -0,0 +1,358 @@
+package dbase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// CopyOptions configures which structure and data is copied by CopyTo
+type CopyOptions struct {
+	Columns       []string        // Names of the columns to copy, all columns if empty
+	Filter        func(*Row) bool // Only rows for which the filter returns true are copied, all rows if nil
+	Deleted       bool            // If true deleted rows are copied (and stay marked as deleted)
+	StructureOnly bool            // If true only the table structure is copied
+	Version       FileVersion     // Version of the new table, the version of the source table if 0
+	MemoBlockSize uint16          // Block size of the new memo file, the block size of the source table if 0
+	IO            IO              // The IO implementation used to create the new table, DefaultIO if nil
+}
+
+// CopyTo creates a new table with the same or a subset of the columns and copies the rows into it (FoxPro COPY TO).
+// If no converter is configured the converter of the source table is used.
+// Memo contents are copied into the memo file of the new table. The new table is returned open.
+// If copying the rows fails the new table is closed and removed (GenericIO tables are only closed).
+func (file *File) CopyTo(config *Config, opts CopyOptions) (*File, error) {
+	positions := make([]int, 0, len(file.table.columns))
+	if len(opts.Columns) == 0 {
+		for i := range file.table.columns {
+			positions = append(positions, i)
+		}
+	}
+	for _, name := range opts.Columns {
+		pos := file.ColumnPosByName(strings.ToUpper(name))
+		if pos < 0 {
+			return nil, newError("dbase-copy-copyto-1", fmt.Errorf("column '%s' not found", name))
+		}
+		positions = append(positions, pos)
+	}
+	columns := make([]*Column, 0, len(positions))
+	for _, pos := range positions {
+		column := *file.table.columns[pos]
+		column.Position = 0
+		columns = append(columns, &column)
+	}
+	if config.Converter == nil {
+		config.Converter = file.config.Converter
+	}
+	version := opts.Version
+	if version == 0 {
+		version = FileVersion(file.header.FileType)
+	}
+	blockSize := opts.MemoBlockSize
+	if blockSize == 0 && file.memoHeader != nil {
+		blockSize = file.memoHeader.BlockSize
+	}
+	debugf("Copying %d columns of table %v to %v", len(columns), file.config.Filename, config.Filename)
+	target, err := New(version, config, columns, blockSize, opts.IO)
+	if err != nil {
+		return nil, newError("dbase-copy-copyto-2", err)
+	}
+	if opts.StructureOnly {
+		return target, nil
+	}
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			target.discard()
+			return nil, newError("dbase-copy-copyto-3", err)
+		}
+		if row.Deleted && !opts.Deleted {
+			continue
+		}
+		if opts.Filter != nil && !opts.Filter(row) {
+			continue
+		}
+		copied := target.NewRow()
+		copied.Deleted = row.Deleted
+		for i, pos := range positions {
+			field := row.fields[pos]
+			value, err := field.Value()
+			if err != nil {
+				target.discard()
+				return nil, newError("dbase-copy-copyto-4", fmt.Errorf("interpreting column %v of row %v failed with error: %w", field.Name(), row.Position, err))
+			}
+			copied.fields[i].value = value
+		}
+		err = copied.Add()
+		if err != nil {
+			target.discard()
+			return nil, newError("dbase-copy-copyto-5", err)
+		}
+	}
+	return target, nil
+}
+
+// AppendFrom appends all not deleted rows of the source table (FoxPro APPEND FROM) and returns the number of appended rows.
+// Columns are matched by name, the mapping maps source column names to different column names of this table.
+// Source columns without a matching column are skipped, mapped columns must exist in both tables. Values are converted to the type of the target column,
+// conversions that would lose data return an error. Autoincrement columns get new values.
+func (file *File) AppendFrom(src *File, mapping map[string]string) (int, error) {
+	if file.config.ReadOnly {
+		return 0, newError("dbase-copy-appendfrom-1", errors.New("table is opened read-only"))
+	}
+	type pair struct {
+		source int
+		target *Column
+	}
+	names := make(map[string]string, len(mapping))
+	for source, target := range mapping {
+		if src.ColumnPosByName(strings.ToUpper(source)) < 0 {
+			return 0, newError("dbase-copy-appendfrom-2", fmt.Errorf("mapped source column '%s' not found", source))
+		}
+		if file.ColumnPosByName(strings.ToUpper(target)) < 0 {
+			return 0, newError("dbase-copy-appendfrom-8", fmt.Errorf("mapped target column '%s' not found", target))
+		}
+		names[strings.ToUpper(source)] = strings.ToUpper(target)
+	}
+	pairs := make([]pair, 0)
+	for i, column := range src.table.columns {
+		name := column.Name()
+		if mapped, ok := names[name]; ok {
+			name = mapped
+		}
+		pos := file.ColumnPosByName(name)
+		if pos < 0 {
+			debugf("Skipping source column %v, no matching column found", column.Name())
+			continue
+		}
+		pairs = append(pairs, pair{source: i, target: file.table.columns[pos]})
+	}
+	if len(pairs) == 0 {
+		return 0, newError("dbase-copy-appendfrom-3", errors.New("no matching columns found"))
+	}
+	count := 0
+	cursor := src.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return count, newError("dbase-copy-appendfrom-4", err)
+		}
+		if row.Deleted {
+			continue
+		}
+		m := make(map[string]interface{}, len(pairs))
+		for _, p := range pairs {
+			field := row.fields[p.source]
+			value, err := file.convertValue(field.GetValue(), field.column, p.target)
+			if err != nil {
+				return count, newError("dbase-copy-appendfrom-5", fmt.Errorf("converting column %v of row %v failed with error: %w", field.Name(), row.Position, err))
+			}
+			m[p.target.Name()] = value
+		}
+		appended, err := file.RowFromMap(m)
+		if err != nil {
+			return count, newError("dbase-copy-appendfrom-6", err)
+		}
+		err = appended.Add()
+		if err != nil {
+			return count, newError("dbase-copy-appendfrom-7", err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Closes and removes a table that could not be completed.
+// Tables of other IO implementations than the default are only closed, as the caller provides their handles.
+func (file *File) discard() {
+	err := file.Close()
+	if err != nil {
+		debugf("Closing table %v failed with error: %v", file.config.Filename, err)
+	}
+	if file.io != IO(DefaultIO) {
+		return
+	}
+	debugf("Removing table %v", file.config.Filename)
+	err = os.Remove(file.config.Filename)
+	if err != nil {
+		debugf("Removing table %v failed with error: %v", file.config.Filename, err)
+	}
+	if file.memoHeader != nil {
+		memo := strings.TrimSuffix(file.config.Filename, filepath.Ext(file.config.Filename)) + string(FPT)
+		err = os.Remove(memo)
+		if err != nil {
+			debugf("Removing memo file %v failed with error: %v", memo, err)
+		}
+	}
+}
+
+// Unrepresentable reports the characters of a value the target encoding of Transcode can not represent
+type Unrepresentable struct {
+	Row        uint32 // Position of the row in the source table
+	Column     string // Name of the column
+	Characters []rune // Characters that can not be represented
+}
+
+// Transcode creates a new table with the same structure and rows encoded with the target converter (e.g. 850 to 1252).
+// Character, Varchar and text memo values are decoded with the converter of this table and encoded with the target,
+// the code page mark of the new table is the mark of the target. Binary columns (BinaryFlag, Varbinary, Blob, General,
+// Picture and binary memos) and all other columns are copied unchanged, deleted rows stay deleted.
+// Values with characters the target can not represent are reported. They are written according to the unencodable policy
+// of the target (see ConverterOptions), with the default policy the first such value fails the transcoding.
+// Character and Varchar values that exceed the column length when encoded with the target (e.g. a double byte code page) fail as well.
+// If the transcoding fails the new table is closed and removed (GenericIO tables are only closed).
+func (file *File) Transcode(target EncodingConverter, out Config) (*File, []Unrepresentable, error) {
+	if target == nil {
+		return nil, nil, newError("dbase-copy-transcode-1", errors.New("missing target converter"))
+	}
+	columns := make([]*Column, 0, len(file.table.columns))
+	for _, c := range file.table.columns {
+		column := *c
+		column.Position = 0
+		columns = append(columns, &column)
+	}
+	out.Converter = target
+	blockSize := uint16(0)
+	if file.memoHeader != nil {
+		blockSize = file.memoHeader.BlockSize
+	}
+	debugf("Transcoding table %v from code page 0x%02x to 0x%02x", file.config.Filename, file.config.Converter.CodePage(), target.CodePage())
+	transcoded, err := New(FileVersion(file.header.FileType), &out, columns, blockSize, out.IO)
+	if err != nil {
+		return nil, nil, newError("dbase-copy-transcode-2", err)
+	}
+	report := make([]Unrepresentable, 0)
+	representable := make(map[rune]bool)
+	for position := uint32(0); position < file.header.RowsCount; position++ {
+		data, err := file.ReadRow(position)
+		if err != nil {
+			transcoded.discard()
+			return nil, report, newError("dbase-copy-transcode-3", err)
+		}
+		row, err := file.bytesToRow(data, position, map[int]bool{})
+		if err != nil {
+			transcoded.discard()
+			return nil, report, newError("dbase-copy-transcode-4", err)
+		}
+		copied := transcoded.NewRow()
+		copied.Deleted = row.Deleted
+		for i, field := range row.fields {
+			value, text, err := file.transcodeValue(field, target)
+			if err != nil {
+				transcoded.discard()
+				return nil, report, newError("dbase-copy-transcode-5", fmt.Errorf("transcoding column %v of row %v failed with error: %w", field.Name(), position, err))
+			}
+			if value == nil {
+				// Copied unchanged
+				copied.fields[i].raw = append([]byte{}, field.raw...)
+				copied.fields[i].row = copied
+				continue
+			}
+			if len(text) > 0 {
+				characters := unrepresentable(text, target, representable)
+				if len(characters) > 0 {
+					report = append(report, Unrepresentable{Row: position, Column: field.Name(), Characters: characters})
+				}
+			}
+			copied.fields[i].value = value
+		}
+		err = copied.Add()
+		if err != nil {
+			transcoded.discard()
+			return nil, report, newError("dbase-copy-transcode-6", fmt.Errorf("writing row %v failed with error: %w", position, err))
+		}
+	}
+	return transcoded, report, nil
+}
+
+// Returns the value of the field for the transcoded table and its text, nil if the raw data is copied unchanged
+func (file *File) transcodeValue(field *Field, target EncodingConverter) (interface{}, string, error) {
+	binary := field.column.Flag&byte(BinaryFlag) != 0
+	switch DataType(field.column.DataType) {
+	case Character:
+		if binary {
+			return nil, "", nil
+		}
+		value, err := file.parseCharacter(field.raw, field.column)
+		if err != nil {
+			return nil, "", newError("dbase-copy-transcodevalue-1", err)
+		}
+		// Double byte targets can need more bytes than the column length, unencodable characters are handled on write
+		encoded, _, err := fromUtf8String([]byte(value.(string)), target)
+		if err == nil && len(bytes.TrimRight(encoded, " ")) > int(field.column.Length) {
+			return nil, "", newError("dbase-copy-transcodevalue-9", fmt.Errorf("encoded value exceeds the column length %v", field.column.Length))
+		}
+		return value, value.(string), nil
+	case Varchar:
+		// V values are not converted when read or written
+		value, err := file.parseVarchar(field.raw, field.column, field.row.nullFlags)
+		if err != nil {
+			return nil, "", newError("dbase-copy-transcodevalue-2", err)
+		}
+		raw, ok := value.(string)
+		if !ok || binary {
+			return value, "", nil
+		}
+		text, err := toUTF8String([]byte(raw), file.config.Converter)
+		if err != nil {
+			return nil, "", newError("dbase-copy-transcodevalue-3", err)
+		}
+		encoded, _, err := fromUtf8String([]byte(text), target)
+		if err != nil {
+			return nil, "", newError("dbase-copy-transcodevalue-4", err)
+		}
+		if len(encoded) > int(field.column.Length) {
+			return nil, "", newError("dbase-copy-transcodevalue-5", fmt.Errorf("encoded value exceeds the column length %v", field.column.Length))
+		}
+		return string(encoded), text, nil
+	case Memo:
+		data, isText, err := file.readRawMemo(field.raw, 0)
+		if err != nil {
+			return nil, "", newError("dbase-copy-transcodevalue-6", err)
+		}
+		if data == nil {
+			// No memo, the empty address is copied
+			return nil, "", nil
+		}
+		if !isText || binary {
+			return data, "", nil
+		}
+		text, err := file.config.Converter.Decode(data)
+		if err != nil {
+			return nil, "", newError("dbase-copy-transcodevalue-7", err)
+		}
+		return string(text), string(text), nil
+	case General, Picture, Blob, Varbinary:
+		value, err := file.interpret(field.raw, field.column, field.row.nullFlags)
+		if err != nil {
+			return nil, "", newError("dbase-copy-transcodevalue-8", err)
+		}
+		return value, "", nil
+	}
+	return nil, "", nil
+}
+
+// Returns the characters of the text the target can not represent, results are cached per character
+func unrepresentable(text string, target EncodingConverter, representable map[rune]bool) []rune {
+	characters := make([]rune, 0)
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			continue
+		}
+		ok, checked := representable[r]
+		if !checked {
+			_, lossy, err := fromUtf8String([]byte(string(r)), target)
+			ok = err == nil && !lossy
+			representable[r] = ok
+		}
+		if !ok {
+			characters = append(characters, r)
+		}
+	}
+	return characters
+}
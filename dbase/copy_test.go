@@ -0,0 +1,31 @@
+package dbase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppendFrom(t *testing.T) {
+	columns := func() []*Column {
+		return []*Column{
+			mustColumn(t, "NAME", Character, 10, 0),
+			mustColumn(t, "QTY", Numeric, 5, 0),
+		}
+	}
+	rows := []map[string]interface{}{
+		{"NAME": "Größenwahn", "QTY": int64(1)},
+		{"NAME": "Ärger", "QTY": int64(2)},
+	}
+	src := createTestTable(t, FoxPro, columns(), rows)
+	file := createTestTable(t, FoxPro, columns(), nil)
+	n, err := file.AppendFrom(src, nil)
+	if err != nil {
+		t.Fatalf("appending failed with error: %v", err)
+	}
+	if n != len(rows) {
+		t.Errorf("appended %v rows, expected %v", n, len(rows))
+	}
+	if appended := readTestTable(t, file); !reflect.DeepEqual(appended, rows) {
+		t.Errorf("appended rows %v, expected %v", appended, rows)
+	}
+}
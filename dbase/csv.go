@@ -0,0 +1,535 @@
+package dbase
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVOptions configures ExportCSV, ImportCSV and NewFromCSV
+type CSVOptions struct {
+	Comma            rune              // Field delimiter, ',' if 0
+	NoHeader         bool              // If true the CSV has no header row and the CSV columns are the table columns in order
+	Mapping          map[string]string // Maps CSV header names to column names, other headers match column names case-insensitively
+	Columns          []string          // Names of the exported columns, all columns if empty
+	DateLayout       string            // Layout of D values, "2006-01-02" if empty
+	DateTimeLayout   string            // Layout of T and @ values, time.RFC3339 if empty
+	True             string            // Representation of true L values, "true" if empty. T, Y, YES and .T. are read as true as well
+	False            string            // Representation of false L values, "false" if empty. F, N, NO and .F. are read as false as well
+	DecimalSeparator rune              // Decimal separator of numbers, '.' if 0
+	Deleted          bool              // If true deleted rows are exported
+	DeletedColumn    string            // Name of the CSV column holding the deleted flag, written on export and read on import if set
+	Memos            bool              // If true memo columns (M, W, G and P) are exported, binary values are base64 encoded
+	MemoBlockSize    uint16            // Block size of the memo file created by NewFromCSV, 64 if 0
+}
+
+// Returns the options with defaults for empty values
+func (opts CSVOptions) defaults() CSVOptions {
+	if opts.Comma == 0 {
+		opts.Comma = ','
+	}
+	if opts.DateLayout == "" {
+		opts.DateLayout = "2006-01-02"
+	}
+	if opts.DateTimeLayout == "" {
+		opts.DateTimeLayout = time.RFC3339
+	}
+	if opts.True == "" {
+		opts.True = "true"
+	}
+	if opts.False == "" {
+		opts.False = "false"
+	}
+	if opts.DecimalSeparator == 0 {
+		opts.DecimalSeparator = '.'
+	}
+	return opts
+}
+
+// ExportCSV writes the rows of the table as CSV and returns the number of written rows.
+// The header contains the column names, mapped back to the CSV header names of the mapping.
+// Memo columns are only exported if enabled, Varbinary values are base64 encoded. Empty values are written as empty strings.
+func ExportCSV(file *File, w io.Writer, opts CSVOptions) (int, error) {
+	opts = opts.defaults()
+	columns := make([]int, 0, len(file.table.columns))
+	for i, column := range file.table.columns {
+		if len(opts.Columns) == 0 && (opts.Memos || !DataType(column.DataType).memo()) {
+			columns = append(columns, i)
+		}
+	}
+	for _, name := range opts.Columns {
+		pos := file.ColumnPosByName(strings.ToUpper(name))
+		if pos < 0 {
+			return 0, newError("dbase-csv-exportcsv-1", fmt.Errorf("column '%s' not found", name))
+		}
+		columns = append(columns, pos)
+	}
+	headers := make(map[string]string, len(opts.Mapping))
+	for header, name := range opts.Mapping {
+		headers[strings.ToUpper(name)] = header
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = opts.Comma
+	if !opts.NoHeader {
+		record := make([]string, 0, len(columns)+1)
+		for _, pos := range columns {
+			name := file.table.columns[pos].Name()
+			if header, ok := headers[name]; ok {
+				name = header
+			}
+			record = append(record, name)
+		}
+		if opts.DeletedColumn != "" {
+			record = append(record, opts.DeletedColumn)
+		}
+		err := writer.Write(record)
+		if err != nil {
+			return 0, newError("dbase-csv-exportcsv-2", err)
+		}
+	}
+	count := 0
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return count, newError("dbase-csv-exportcsv-3", err)
+		}
+		if row.Deleted && !opts.Deleted {
+			continue
+		}
+		record := make([]string, 0, len(columns)+1)
+		for _, pos := range columns {
+			field := row.fields[pos]
+			value, err := field.Value()
+			if err != nil {
+				return count, newError("dbase-csv-exportcsv-4", fmt.Errorf("interpreting column %v of row %v failed with error: %w", field.Name(), row.Position, err))
+			}
+			record = append(record, formatCSV(value, field.column, opts))
+		}
+		if opts.DeletedColumn != "" {
+			record = append(record, formatCSV(row.Deleted, nil, opts))
+		}
+		err = writer.Write(record)
+		if err != nil {
+			return count, newError("dbase-csv-exportcsv-5", err)
+		}
+		count++
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return count, newError("dbase-csv-exportcsv-6", err)
+	}
+	return count, nil
+}
+
+// ImportCSV appends the CSV records to the table and returns the number of appended rows.
+// CSV columns are matched to the table columns by header (see CSVOptions.Mapping), unmatched CSV columns are skipped.
+// Values are parsed according to the column type, empty values are nil. Autoincrement columns get new values.
+func ImportCSV(r io.Reader, file *File, opts CSVOptions) (int, error) {
+	if file.config.ReadOnly {
+		return 0, newError("dbase-csv-importcsv-1", errors.New("table is opened read-only"))
+	}
+	opts = opts.defaults()
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Comma
+	reader.FieldsPerRecord = -1
+	var header []string
+	if !opts.NoHeader {
+		record, err := reader.Read()
+		if err != nil {
+			return 0, newError("dbase-csv-importcsv-2", fmt.Errorf("reading CSV header failed with error: %w", err))
+		}
+		header = append([]string{}, record...)
+	}
+	count, err := file.appendCSV(header, func() ([]string, int, error) {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, 0, err
+		}
+		line, _ := reader.FieldPos(0)
+		return record, line, nil
+	}, opts)
+	if err != nil {
+		return count, newError("dbase-csv-importcsv-3", err)
+	}
+	return count, nil
+}
+
+// NewFromCSV creates a new table (see New) with columns inferred from the CSV and imports the records, the CSV is read at once.
+// Column names are the upper case headers (see CSVOptions.Mapping) shortened to 10 characters, COL1, COL2, ... without header.
+// The column types are inferred from the non-empty values: L for logicals, N for numbers, D and T for dates and times
+// matching the layouts, C for other text up to 254 bytes and M for longer text. Columns without values are C columns.
+func NewFromCSV(r io.Reader, version FileVersion, config *Config, opts CSVOptions) (*File, int, error) {
+	opts = opts.defaults()
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Comma
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, 0, newError("dbase-csv-newfromcsv-1", err)
+	}
+	if len(records) == 0 {
+		return nil, 0, newError("dbase-csv-newfromcsv-2", errors.New("no CSV columns found"))
+	}
+	var header []string
+	var width int
+	if opts.NoHeader {
+		width = len(records[0])
+	} else {
+		header = records[0]
+		records = records[1:]
+		width = len(header)
+	}
+	names := make([]string, 0, width)
+	columns := make([]*Column, 0, width)
+	used := make(map[string]bool)
+	location := config.Location
+	if location == nil {
+		location = time.UTC
+	}
+	for i := 0; i < width; i++ {
+		name := fmt.Sprintf("COL%d", i+1)
+		if header != nil {
+			name = header[i]
+			if mapped, ok := opts.Mapping[name]; ok {
+				name = mapped
+			}
+			if opts.DeletedColumn != "" && strings.EqualFold(header[i], opts.DeletedColumn) {
+				continue
+			}
+		}
+		name = columnName(name, used)
+		values := make([]string, 0, len(records))
+		for _, record := range records {
+			if i < len(record) {
+				values = append(values, record[i])
+			}
+		}
+		column, err := inferColumn(name, values, opts, location)
+		if err != nil {
+			return nil, 0, newError("dbase-csv-newfromcsv-3", err)
+		}
+		names = append(names, name)
+		columns = append(columns, column)
+	}
+	if header == nil {
+		// Records without header are matched by position, use the column names as header
+		header = names
+	} else {
+		// The header is matched against the created columns
+		mapped := make([]string, len(header))
+		n := 0
+		for i, h := range header {
+			if opts.DeletedColumn != "" && strings.EqualFold(h, opts.DeletedColumn) {
+				mapped[i] = h
+				continue
+			}
+			mapped[i] = names[n]
+			n++
+		}
+		header = mapped
+		opts.Mapping = nil
+	}
+	file, err := New(version, config, columns, opts.MemoBlockSize, config.IO)
+	if err != nil {
+		return nil, 0, newError("dbase-csv-newfromcsv-4", err)
+	}
+	line := 1
+	if !opts.NoHeader {
+		line = 2
+	}
+	count, err := file.appendCSV(header, func() ([]string, int, error) {
+		if len(records) == 0 {
+			return nil, 0, io.EOF
+		}
+		record := records[0]
+		records = records[1:]
+		line++
+		return record, line - 1, nil
+	}, opts)
+	if err != nil {
+		return file, count, newError("dbase-csv-newfromcsv-5", err)
+	}
+	return file, count, nil
+}
+
+// Appends the records returned by next until io.EOF, the header is nil if the records match the columns by position
+func (file *File) appendCSV(header []string, next func() ([]string, int, error), opts CSVOptions) (int, error) {
+	columns := make(map[int]*Column)
+	deleted := -1
+	if header == nil {
+		for i, column := range file.table.columns {
+			columns[i] = column
+		}
+	}
+	for i, h := range header {
+		if opts.DeletedColumn != "" && strings.EqualFold(h, opts.DeletedColumn) {
+			deleted = i
+			continue
+		}
+		name := h
+		if mapped, ok := opts.Mapping[h]; ok {
+			name = mapped
+		}
+		pos := file.ColumnPosByName(strings.ToUpper(strings.TrimSpace(name)))
+		if pos < 0 {
+			debugf("Skipping CSV column %v, no matching column found", h)
+			continue
+		}
+		columns[i] = file.table.columns[pos]
+	}
+	if len(columns) == 0 {
+		return 0, newError("dbase-csv-appendcsv-1", errors.New("no matching columns found"))
+	}
+	count := 0
+	for {
+		record, line, err := next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, newError("dbase-csv-appendcsv-2", err)
+		}
+		m := make(map[string]interface{}, len(columns))
+		for i, value := range record {
+			column, ok := columns[i]
+			if !ok || column.Flag&byte(AutoincrementFlag) == byte(AutoincrementFlag) {
+				continue
+			}
+			parsed, err := file.parseCSV(value, column, opts)
+			if err != nil {
+				return count, newError("dbase-csv-appendcsv-3", fmt.Errorf("parsing column %v in line %v failed with error: %w", column.Name(), line, err))
+			}
+			m[column.Name()] = parsed
+		}
+		row, err := file.RowFromMap(m)
+		if err != nil {
+			return count, newError("dbase-csv-appendcsv-4", fmt.Errorf("line %v: %w", line, err))
+		}
+		if deleted >= 0 && deleted < len(record) {
+			d, err := file.parseCSV(record[deleted], &Column{DataType: byte(Logical)}, opts)
+			if err != nil {
+				return count, newError("dbase-csv-appendcsv-5", fmt.Errorf("parsing deleted flag in line %v failed with error: %w", line, err))
+			}
+			row.Deleted = d == true
+		}
+		err = row.Add()
+		if err != nil {
+			return count, newError("dbase-csv-appendcsv-6", fmt.Errorf("line %v: %w", line, err))
+		}
+		count++
+	}
+}
+
+// Formats the value of the column for CSV, a nil column formats the deleted flag
+func formatCSV(value interface{}, column *Column, opts CSVOptions) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if column != nil && DataType(column.DataType) == Character {
+			return strings.TrimRight(v, " ")
+		}
+		return v
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case bool:
+		if v {
+			return opts.True
+		}
+		return opts.False
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		if DataType(column.DataType) == Date {
+			return v.Format(opts.DateLayout)
+		}
+		return v.Format(opts.DateTimeLayout)
+	case float64:
+		decimals := -1
+		if t := DataType(column.DataType); (t == Numeric || t == Float) && column.Decimals > 0 {
+			decimals = int(column.Decimals)
+		}
+		return formatDecimalSeparator(strconv.FormatFloat(v, 'f', decimals, 64), opts)
+	case Decimal:
+		return formatDecimalSeparator(v.String(), opts)
+	}
+	return formatDecimalSeparator(fmt.Sprint(value), opts)
+}
+
+// Replaces the decimal point of a formatted number
+func formatDecimalSeparator(s string, opts CSVOptions) string {
+	if opts.DecimalSeparator == '.' {
+		return s
+	}
+	return strings.Replace(s, ".", string(opts.DecimalSeparator), 1)
+}
+
+// Parses the CSV value according to the column type, empty values are nil.
+// Dates and times without zone are in the location of the table.
+func (file *File) parseCSV(value string, column *Column, opts CSVOptions) (interface{}, error) {
+	dataType := DataType(column.DataType)
+	switch dataType {
+	case Character, Varchar:
+		return value, nil
+	case Memo:
+		if value == "" {
+			return nil, nil
+		}
+		return value, nil
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	switch dataType {
+	case Logical:
+		b, ok := parseCSVLogical(value, opts)
+		if !ok {
+			return nil, newError("dbase-csv-parsecsv-1", fmt.Errorf("invalid logical value %q", value))
+		}
+		return b, nil
+	case Date, DateTime, Timestamp:
+		layout := opts.DateTimeLayout
+		if dataType == Date {
+			layout = opts.DateLayout
+		}
+		if t, err := time.ParseInLocation(layout, value, file.location()); err == nil {
+			return t, nil
+		}
+		// Parsed with the date layouts of the table
+		return value, nil
+	case Varbinary, Blob, General, Picture:
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, newError("dbase-csv-parsecsv-2", err)
+		}
+		return data, nil
+	}
+	if opts.DecimalSeparator != '.' {
+		value = strings.Replace(value, string(opts.DecimalSeparator), ".", 1)
+	}
+	return json.Number(value), nil
+}
+
+// Parses a logical value, returns false if the value is no logical
+func parseCSVLogical(value string, opts CSVOptions) (bool, bool) {
+	switch strings.ToUpper(strings.Trim(value, ".")) {
+	case strings.ToUpper(opts.True), "T", "TRUE", "Y", "YES":
+		return true, true
+	case strings.ToUpper(opts.False), "F", "FALSE", "N", "NO":
+		return false, true
+	}
+	return false, false
+}
+
+// Returns a unique column name (upper case, A-Z, 0-9 and _, 10 characters) for the CSV header
+func columnName(header string, used map[string]bool) string {
+	name := []byte(strings.ToUpper(strings.TrimSpace(header)))
+	for i, c := range name {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] >= '0' && name[0] <= '9' {
+		name = append([]byte("C"), name...)
+	}
+	if len(name) > 10 {
+		name = name[:10]
+	}
+	unique := string(name)
+	for i := 1; used[unique]; i++ {
+		suffix := strconv.Itoa(i)
+		base := string(name)
+		if len(base)+len(suffix) > 10 {
+			base = base[:10-len(suffix)]
+		}
+		unique = base + suffix
+	}
+	used[unique] = true
+	return unique
+}
+
+// Infers the column type from the non-empty CSV values
+func inferColumn(name string, values []string, opts CSVOptions, location *time.Location) (*Column, error) {
+	logical, number, date, dateTime := true, true, true, true
+	length, integers, decimals, count := 0, 0, 0, 0
+	for _, value := range values {
+		if len(value) > length {
+			length = len(value)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		count++
+		if _, ok := parseCSVLogical(value, opts); !ok {
+			logical = false
+		}
+		if number {
+			i, d, ok := numberDigits(value, opts.DecimalSeparator)
+			number = ok
+			if i > integers {
+				integers = i
+			}
+			if d > decimals {
+				decimals = d
+			}
+		}
+		if _, err := time.ParseInLocation(opts.DateLayout, value, location); err != nil {
+			date = false
+		}
+		if _, err := time.ParseInLocation(opts.DateTimeLayout, value, location); err != nil {
+			dateTime = false
+		}
+	}
+	numberLength := integers
+	if decimals > 0 {
+		numberLength += decimals + 1
+	}
+	switch {
+	case count == 0:
+		return NewColumn(name, Character, 1, 0, false)
+	case logical:
+		return NewColumn(name, Logical, 1, 0, false)
+	case number && numberLength <= 20:
+		return NewColumn(name, Numeric, uint8(numberLength), uint8(decimals), false)
+	case date:
+		return NewColumn(name, Date, 8, 0, false)
+	case dateTime:
+		return NewColumn(name, DateTime, 8, 0, false)
+	case length <= 254:
+		return NewColumn(name, Character, uint8(length), 0, false)
+	}
+	return NewColumn(name, Memo, 4, 0, false)
+}
+
+// Returns the number of integer digits (including the sign) and decimals of a plain decimal number.
+// Numbers with leading zeros (e.g. zip codes) are no numbers.
+func numberDigits(value string, separator rune) (int, int, bool) {
+	integer, fraction := value, ""
+	if i := strings.IndexRune(value, separator); i >= 0 {
+		integer, fraction = value[:i], value[i+len(string(separator)):]
+		if fraction == "" {
+			return 0, 0, false
+		}
+	}
+	digits := strings.TrimLeft(integer, "+-")
+	if len(integer)-len(digits) > 1 || digits == "" || len(digits) > 1 && digits[0] == '0' {
+		return 0, 0, false
+	}
+	for _, s := range []string{digits, fraction} {
+		for _, c := range s {
+			if c < '0' || c > '9' {
+				return 0, 0, false
+			}
+		}
+	}
+	return len(integer), len(fraction), true
+}
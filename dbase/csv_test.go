@@ -0,0 +1,67 @@
+package dbase
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewFromCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		opts    CSVOptions
+		columns []string
+		types   []DataType
+		rows    []map[string]interface{}
+	}{
+		{
+			name:    "header only",
+			csv:     "a,b\n",
+			columns: []string{"A", "B"},
+			types:   []DataType{Character, Character},
+			rows:    []map[string]interface{}{},
+		},
+		{
+			name:    "no header",
+			csv:     "1,x\n2,y\n",
+			opts:    CSVOptions{NoHeader: true},
+			columns: []string{"COL1", "COL2"},
+			types:   []DataType{Numeric, Character},
+			rows: []map[string]interface{}{
+				{"COL1": int64(1), "COL2": "x"},
+				{"COL1": int64(2), "COL2": "y"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chdirTemp(t)
+			file, n, err := NewFromCSV(strings.NewReader(test.csv), FoxProAutoincrement, testConfig(), test.opts)
+			if err != nil {
+				t.Fatalf("creating the table failed with error: %v", err)
+			}
+			defer file.Close()
+			if n != len(test.rows) {
+				t.Errorf("imported %v rows, expected %v", n, len(test.rows))
+			}
+			columns := make([]string, 0)
+			types := make([]DataType, 0)
+			for _, column := range file.Columns() {
+				columns = append(columns, column.Name())
+				types = append(types, DataType(column.DataType))
+			}
+			if !reflect.DeepEqual(columns, test.columns) || !reflect.DeepEqual(types, test.types) {
+				t.Errorf("created columns %v of types %v, expected %v of types %v", columns, types, test.columns, test.types)
+			}
+			if rows := readTestTable(t, file); !reflect.DeepEqual(rows, test.rows) {
+				t.Errorf("imported rows %v, expected %v", rows, test.rows)
+			}
+		})
+	}
+	chdirTemp(t)
+	_, _, err := NewFromCSV(strings.NewReader(""), FoxProAutoincrement, testConfig(), CSVOptions{})
+	if err == nil {
+		t.Error("creating a table from an empty CSV succeeded")
+	}
+}
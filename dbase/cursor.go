@@ -0,0 +1,121 @@
+package dbase
+
+import (
+	"fmt"
+)
+
+// Cursor is a row pointer on a table that can be moved independently of other cursors.
+// The row pointer methods of File (GoTo, Skip, Next, Row, EOF, BOF, Pointer, Deleted) use the default cursor of the file,
+// GoTo, Skip and Deleted are called through the IO implementation, the built-in implementations move the default cursor.
+// A cursor is not safe for concurrent use, but multiple cursors on the same file can be used concurrently for reading.
+type Cursor struct {
+	file    *File  // The file the cursor is moving on
+	pointer uint32 // Position of the cursor
+}
+
+// Creates a new cursor positioned at the first row of the table
+func (file *File) NewCursor() *Cursor {
+	return &Cursor{file: file}
+}
+
+// Returns the default cursor used by the row pointer methods of the file
+func (file *File) Cursor() *Cursor {
+	file.table.cursorOnce.Do(func() {
+		file.table.cursor = file.NewCursor()
+	})
+	return file.table.cursor
+}
+
+// Returns the file the cursor is moving on
+func (cursor *Cursor) File() *File {
+	return cursor.file
+}
+
+// GoTo sets the cursor to row
+// Returns and EOF error if at EOF and positions the cursor at lastRow+1
+func (cursor *Cursor) GoTo(row uint32) error {
+	if row > cursor.file.header.RowsCount {
+		cursor.pointer = cursor.file.header.RowsCount
+		return newError("dbase-cursor-goto-1", fmt.Errorf("%w, go to %v > %v", ErrEOF, row, cursor.file.header.RowsCount))
+	}
+	debugf("Going to row: %d", row)
+	cursor.pointer = row
+	return nil
+}
+
+// Skip adds offset to the cursor position
+// If at end of file positions the cursor at lastRow+1
+// If the cursor would be become negative positions the cursor at 0
+// Does not skip deleted rows
+func (cursor *Cursor) Skip(offset int64) {
+	newval := int64(cursor.pointer) + offset
+	switch {
+	case newval >= int64(cursor.file.header.RowsCount):
+		cursor.pointer = cursor.file.header.RowsCount
+	case newval < 0:
+		cursor.pointer = 0
+	default:
+		cursor.pointer = uint32(newval)
+	}
+	debugf("Skipping %d row/s, new position: %d", offset, cursor.pointer)
+}
+
+// Returns if the cursor is at end of file
+func (cursor *Cursor) EOF() bool {
+	return cursor.pointer >= cursor.file.header.RowsCount
+}
+
+// Returns if the cursor is before first row
+func (cursor *Cursor) BOF() bool {
+	return cursor.pointer == 0
+}
+
+// Returns the current cursor position
+func (cursor *Cursor) Pointer() uint32 {
+	return cursor.pointer
+}
+
+// Returns the row at the cursor position
+func (cursor *Cursor) Row() (*Row, error) {
+	data, err := cursor.file.ReadRow(cursor.pointer)
+	if err != nil {
+		return nil, newError("dbase-cursor-row-1", err)
+	}
+	return cursor.file.bytesToRow(data, cursor.pointer, cursor.file.table.projection)
+}
+
+// Reads the row and increments the cursor by one
+func (cursor *Cursor) Next() (*Row, error) {
+	row, err := cursor.Row()
+	cursor.Skip(1)
+	if err != nil {
+		return nil, newError("dbase-cursor-next-1", err)
+	}
+	return row, nil
+}
+
+// Decrements the cursor by one and reads the row
+// Starting at EOF this iterates the table backwards until BOF
+func (cursor *Cursor) Prev() (*Row, error) {
+	if cursor.BOF() {
+		return nil, newError("dbase-cursor-prev-1", ErrBOF)
+	}
+	cursor.Skip(-1)
+	row, err := cursor.Row()
+	if err != nil {
+		return nil, newError("dbase-cursor-prev-2", err)
+	}
+	return row, nil
+}
+
+// Returns if the row at the cursor position is deleted
+func (cursor *Cursor) Deleted() (bool, error) {
+	if cursor.EOF() {
+		return false, newError("dbase-cursor-deleted-1", ErrEOF)
+	}
+	data, err := cursor.file.ReadRow(cursor.pointer)
+	if err != nil {
+		return false, newError("dbase-cursor-deleted-2", err)
+	}
+	return Marker(data[0]) == Deleted, nil
+}
@@ -0,0 +1,204 @@
+package dbase
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number consisting of an unscaled integer and a scale (number of decimal places).
+// The value is unscaled * 10^-scale. Decimal values are returned for N (with decimals), F and Y columns
+// if Config.ExactDecimals is set and can always be written to these columns.
+// The zero value is 0 with scale 0. Compare decimals with Cmp, == compares the internal *big.Int pointers.
+type Decimal struct {
+	unscaled *big.Int
+	scale    uint8
+}
+
+var bigTen = big.NewInt(10)
+
+// NewDecimal returns the decimal unscaled * 10^-scale, e.g. NewDecimal(12345, 2) is 123.45
+func NewDecimal(unscaled int64, scale uint8) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// ParseDecimal parses a decimal number like "-123.45" or "1.5E3", leading and trailing spaces are ignored
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	mantissa, exponent := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return Decimal{}, newError("dbase-decimal-parsedecimal-1", fmt.Errorf("invalid decimal %q", s))
+		}
+		mantissa, exponent = s[:i], e
+	}
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	digits := integer + fraction
+	if len(digits) == 0 || digits == "-" || digits == "+" || strings.ContainsAny(digits[1:], "+-") {
+		return Decimal{}, newError("dbase-decimal-parsedecimal-2", fmt.Errorf("invalid decimal %q", s))
+	}
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, newError("dbase-decimal-parsedecimal-3", fmt.Errorf("invalid decimal %q", s))
+	}
+	scale := len(fraction) - exponent
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(uint8(-scale)))
+		scale = 0
+	}
+	if scale > math.MaxUint8 {
+		return Decimal{}, newError("dbase-decimal-parsedecimal-4", fmt.Errorf("decimal %q has too many decimal places", s))
+	}
+	return Decimal{unscaled: unscaled, scale: uint8(scale)}, nil
+}
+
+// DecimalFromFloat returns the float rounded to the scale, NaN and infinite values return an error
+func DecimalFromFloat(f float64, scale uint8) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, newError("dbase-decimal-decimalfromfloat-1", fmt.Errorf("can not convert %v to a decimal", f))
+	}
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', int(scale), 64))
+	if err != nil {
+		return Decimal{}, newError("dbase-decimal-decimalfromfloat-2", err)
+	}
+	return d, nil
+}
+
+// Returns 10^n
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// Returns the unscaled value, the zero value is treated as 0
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale returns the number of decimal places
+func (d Decimal) Scale() uint8 {
+	return d.scale
+}
+
+// Unscaled returns a copy of the unscaled integer value
+func (d Decimal) Unscaled() *big.Int {
+	return new(big.Int).Set(d.int())
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the decimal
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// Cmp compares the decimals and returns -1, 0 or 1
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := d.int(), other.int()
+	if d.scale < other.scale {
+		a = new(big.Int).Mul(a, pow10(other.scale-d.scale))
+	} else if d.scale > other.scale {
+		b = new(big.Int).Mul(b, pow10(d.scale-other.scale))
+	}
+	return a.Cmp(b)
+}
+
+// Round returns the decimal rounded half away from zero to the scale, a larger scale appends zeros
+func (d Decimal) Round(scale uint8) Decimal {
+	if scale >= d.scale {
+		return Decimal{unscaled: new(big.Int).Mul(d.int(), pow10(scale-d.scale)), scale: scale}
+	}
+	divisor := pow10(d.scale - scale)
+	quotient, remainder := new(big.Int).QuoRem(d.int(), divisor, new(big.Int))
+	if remainder.Abs(remainder).Lsh(remainder, 1).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(d.Sign())))
+	}
+	return Decimal{unscaled: quotient, scale: scale}
+}
+
+// Int64 returns the integer value and true if the decimal has no fraction and fits into an int64
+func (d Decimal) Int64() (int64, bool) {
+	quotient, remainder := new(big.Int).QuoRem(d.int(), pow10(d.scale), new(big.Int))
+	if remainder.Sign() != 0 || !quotient.IsInt64() {
+		return 0, false
+	}
+	return quotient.Int64(), true
+}
+
+// Float64 returns the nearest float64 value
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns the decimal with all decimal places, e.g. "-0.50"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	if d.scale > 0 {
+		if len(digits) <= int(d.scale) {
+			digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes the decimal as JSON number without loss
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or string into the decimal
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	return d.UnmarshalText(bytes.Trim(data, `"`))
+}
+
+// Returns a numeric column value (int32, int64, float64 or Decimal) as decimal
+func toDecimal(value interface{}, scale uint8) (Decimal, bool, error) {
+	switch v := value.(type) {
+	case Decimal:
+		return v, true, nil
+	case float64:
+		d, err := DecimalFromFloat(v, scale)
+		return d, true, err
+	case int64:
+		return NewDecimal(v, 0), true, nil
+	case int32:
+		return NewDecimal(int64(v), 0), true, nil
+	}
+	return Decimal{}, false, nil
+}
+
+// Returns the decimal rounded to the column decimals as text, values exceeding the column length return an error
+func (d Decimal) format(column *Column) ([]byte, error) {
+	s := d.Round(column.Decimals).String()
+	if len(s) > int(column.Length) {
+		return nil, newError("dbase-decimal-format-1", fmt.Errorf("value %v exceeds the column length %v at column field: %v", s, column.Length, column.Name()))
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,118 @@
+package dbase
+
+import (
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in    string
+		out   string
+		scale uint8
+	}{
+		{"123.45", "123.45", 2},
+		{" -0.5 ", "-0.5", 1},
+		{"1.5E3", "1500", 0},
+		{"1.25e-1", "0.125", 3},
+		{"+7", "7", 0},
+		{".5", "0.5", 1},
+	}
+	for _, test := range tests {
+		d, err := ParseDecimal(test.in)
+		if err != nil {
+			t.Fatalf("ParseDecimal(%q) failed with error: %v", test.in, err)
+		}
+		if d.String() != test.out || d.Scale() != test.scale {
+			t.Errorf("ParseDecimal(%q) = %v (scale %v), expected %v (scale %v)", test.in, d, d.Scale(), test.out, test.scale)
+		}
+	}
+	for _, in := range []string{"", "-", "1-2", "1.2.3", "abc", "1e"} {
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q) expected an error", in)
+		}
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		in    string
+		scale uint8
+		out   string
+	}{
+		{"1.005", 2, "1.01"},
+		{"-1.005", 2, "-1.01"},
+		{"1.004", 2, "1.00"},
+		{"2.5", 0, "3"},
+		{"-2.5", 0, "-3"},
+		{"1.5", 3, "1.500"},
+	}
+	for _, test := range tests {
+		d, err := ParseDecimal(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := d.Round(test.scale).String(); out != test.out {
+			t.Errorf("%v.Round(%v) = %v, expected %v", test.in, test.scale, out, test.out)
+		}
+	}
+}
+
+func TestDecimalCmp(t *testing.T) {
+	a, _ := ParseDecimal("1.50")
+	b, _ := ParseDecimal("1.5")
+	if a.Cmp(b) != 0 {
+		t.Errorf("%v.Cmp(%v) = %v, expected 0", a, b, a.Cmp(b))
+	}
+	if NewDecimal(-1, 0).Cmp(Decimal{}) != -1 || (Decimal{}).Cmp(NewDecimal(1, 3)) != -1 {
+		t.Error("negative decimals have to be less than zero and zero less than positive decimals")
+	}
+}
+
+func TestNumericRepresentation(t *testing.T) {
+	column := &Column{DataType: byte(Numeric), Length: 6, Decimals: 2}
+	d, _ := ParseDecimal("12.345")
+	large, _ := ParseDecimal("1234.56")
+	tests := []struct {
+		value interface{}
+		out   string
+		lossy bool
+	}{
+		{int64(42), "    42", false},
+		{float64(1.5), "  1.50", false},
+		{d, " 12.35", false},
+		{float64(1234.567), "******", true},
+		{int64(12345678), "******", true},
+		{large, "******", true},
+	}
+	file := &File{config: &Config{}}
+	for _, test := range tests {
+		field := &Field{column: column, value: test.value}
+		raw, err := file.getNumericRepresentation(field, false)
+		if err != nil {
+			t.Fatalf("representation of %v failed with error: %v", test.value, err)
+		}
+		if string(raw) != test.out || field.lossy != test.lossy {
+			t.Errorf("representation of %v = %q (lossy %v), expected %q (lossy %v)", test.value, raw, field.lossy, test.out, test.lossy)
+		}
+	}
+	for _, value := range []interface{}{float64(1234.567), int64(12345678)} {
+		raw, _ := file.getNumericRepresentation(&Field{column: column, value: value}, false)
+		if parsed, err := file.parseNumeric(raw, column); err != nil || parsed != nil {
+			t.Errorf("overflow of %v is read back as %v (error %v), expected nil", value, parsed, err)
+		}
+	}
+	float := &Column{DataType: byte(Float), Length: 6, Decimals: 2}
+	raw, err := file.getFloatRepresentation(&Field{column: float, value: float64(12345.6)}, false)
+	if err != nil || string(raw) != "******" {
+		t.Errorf("float representation of 12345.6 = %q (error %v), expected overflow", raw, err)
+	}
+	strict := &File{config: &Config{Strict: true}}
+	_, err = strict.getNumericRepresentation(&Field{column: column, value: float64(1234.567)}, false)
+	if err == nil {
+		t.Error("expected an error for a number exceeding the column length with Config.Strict")
+	}
+	_, err = strict.getFloatRepresentation(&Field{column: &Column{DataType: byte(Float), Length: 4, Decimals: 2}, value: float64(105.67)}, false)
+	if err == nil {
+		t.Error("expected an error for a float exceeding the column length with Config.Strict")
+	}
+}
@@ -0,0 +1,266 @@
+package dbase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+)
+
+// Number of rows sampled by OpenTable to detect the encoding of tables without code page mark
+const detectSampleRows = 100
+
+// Maximum number of bytes sampled of one memo
+const detectMemoSize = 4096
+
+// Frequent non-ASCII letters (lowercase) of the languages of the supported code pages
+const detectCommonLetters = "äöüßéèêàâçñóíúáåøæôîûëïœąćęłńśźżčďěňřšťůžőűğışãõ" +
+	"αάεέηήιίκλμνοόπρσςτυύ" +
+	"оеаинтсрвлкмдпуяыьгзбчй" +
+	"ابتدرسعلمنهويةأإآ" +
+	"יוהאלרמתבנשעדםן" +
+	"กงดนบมยรลวสอาเแ"
+
+// Hebrew final forms, only used at the end of a word
+const detectHebrewFinals = "ךםןףץ"
+
+// Frequent Han characters of simplified (GBK) and traditional (Big5) Chinese and Japanese
+const detectCommonHan = "的一是不了在人有我他这个们中来上大为和国地到以说时要就出会可也你对生能而子那得于着下自之年过发后作里用道行所然家种事成方多经么去法学如都同现当没动面起看定天分还进好小部其些主样理心本前开但因只从想实日" +
+	"這個們來為國說時會對於過發後裡種經麼學現當沒動還進樣開從實" +
+	"月市区町村県都府道名前住所電話番号社員会社株式"
+
+// Script groups used to check that the letters of a word belong together
+const (
+	scriptUnknown = iota
+	scriptLatin
+	scriptGreek
+	scriptCyrillic
+	scriptArabic
+	scriptHebrew
+	scriptThai
+	scriptHan // Han, Hiragana and Katakana
+	scriptHangul
+)
+
+// DetectEncoding proposes a converter for a table without (or with a wrong) code page mark.
+// Character and text memo values of up to sample rows (all rows if sample is 0) are decoded with every supported encoding
+// and scored by invalid bytes, unlikely symbols, mixed scripts within words and common letters of the languages.
+// Returns ErrUnknownCodePage if the sample contains no non-ASCII characters.
+func DetectEncoding(file *File, sample int) (DefaultConverter, error) {
+	if file == nil || file.header == nil || file.table == nil {
+		return DefaultConverter{}, newError("dbase-detect-detectencoding-1", errors.New("table is not opened"))
+	}
+	texts, err := file.sampleTexts(sample)
+	if err != nil {
+		return DefaultConverter{}, newError("dbase-detect-detectencoding-2", err)
+	}
+	if len(texts) == 0 {
+		return DefaultConverter{}, newError("dbase-detect-detectencoding-3", fmt.Errorf("%w: no non-ASCII characters found to detect the encoding", ErrUnknownCodePage))
+	}
+	var best encoding.Encoding
+	bestScore := 0
+	seen := make(map[encoding.Encoding]bool)
+	for _, cp := range codePages {
+		if cp.encoding == nil || seen[cp.encoding] {
+			continue
+		}
+		seen[cp.encoding] = true
+		score := 0
+		decoder := cp.encoding.NewDecoder()
+		for _, text := range texts {
+			decoded, err := decoder.Bytes(text)
+			if err != nil {
+				score -= 5 * len(text)
+				continue
+			}
+			score += scoreText(string(decoded))
+		}
+		debugf("Detecting encoding => code page %d scored %d", cp.number, score)
+		if best == nil || score > bestScore {
+			best = cp.encoding
+			bestScore = score
+		}
+	}
+	converter := NewDefaultConverter(best)
+	debugf("Detected encoding with code page mark 0x%02x", converter.CodePage())
+	return converter, nil
+}
+
+// Returns the raw non-ASCII Character and text memo values of up to sample rows
+func (file *File) sampleTexts(sample int) ([][]byte, error) {
+	rows := file.header.RowsCount
+	if sample > 0 && uint32(sample) < rows {
+		rows = uint32(sample)
+	}
+	texts := make([][]byte, 0)
+	for position := uint32(0); position < rows; position++ {
+		data, err := file.ReadRow(position)
+		if err != nil {
+			return nil, newError("dbase-detect-sampletexts-1", err)
+		}
+		for _, column := range file.table.columns {
+			if int(column.Position)+int(column.Length) > len(data) {
+				continue
+			}
+			raw := data[column.Position : column.Position+uint32(column.Length)]
+			switch DataType(column.DataType) {
+			case Character:
+				raw = sanitizeString(raw)
+			case Memo:
+				if column.Flag&byte(BinaryFlag) != 0 {
+					continue
+				}
+				var text bool
+				raw, text, err = file.readRawMemo(raw, detectMemoSize)
+				if err != nil {
+					return nil, newError("dbase-detect-sampletexts-2", err)
+				}
+				if !text {
+					continue
+				}
+			default:
+				continue
+			}
+			if !isASCII(raw) {
+				texts = append(texts, raw)
+			}
+		}
+	}
+	return texts, nil
+}
+
+// Scores the plausibility of a decoded text, higher is more plausible
+func scoreText(text string) int {
+	score := 0
+	word := make([]rune, 0)
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) {
+			word = append(word, r)
+			continue
+		}
+		score += scoreWord(word)
+		word = word[:0]
+		switch {
+		case r == utf8.RuneError:
+			score -= 10
+		case unicode.IsControl(r) && r != '\t' && r != '\r' && r != '\n':
+			score -= 5
+		case r < utf8.RuneSelf, unicode.IsPunct(r), unicode.IsSpace(r), unicode.Is(unicode.Sc, r), r == '°':
+		default:
+			// Symbols, box drawing and other characters that are unlikely in text
+			score--
+		}
+	}
+	return score + scoreWord(word)
+}
+
+// Scores the non-ASCII letters of a word, letters of different scripts and unlikely letter sequences are penalized.
+// Letters of double byte encodings score per byte.
+func scoreWord(word []rune) int {
+	ascii, other, score := 0, 0, 0
+	script := scriptUnknown
+	mixed, unlikely := false, false
+	for i, r := range word {
+		if r < utf8.RuneSelf {
+			ascii++
+			if script != scriptUnknown && script != scriptLatin {
+				mixed = true
+			}
+			script = scriptLatin
+			continue
+		}
+		other++
+		s := runeScript(r)
+		if script != scriptUnknown && s != scriptUnknown && s != script {
+			mixed = true
+		}
+		if s != scriptUnknown {
+			script = s
+		}
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks follow a letter
+			unlikely = unlikely || i == 0
+			continue
+		case i > 0 && unicode.IsUpper(r) && unicode.IsLower(word[i-1]):
+			unlikely = true
+		case i < len(word)-1 && strings.ContainsRune(detectHebrewFinals, r):
+			unlikely = true
+		}
+		switch {
+		case s == scriptHan && strings.ContainsRune(detectCommonHan, r):
+			score += 6
+		case r >= 0xFF61 && r <= 0xFF9F:
+			// Halfwidth katakana
+			score++
+		case s == scriptHan && !unicode.Is(unicode.Han, r), s == scriptHangul:
+			score += 4
+		case s == scriptHan:
+			score += 3
+		case strings.ContainsRune(detectCommonLetters, unicode.ToLower(r)):
+			score += 2
+		default:
+			score++
+		}
+	}
+	if other == 0 {
+		return 0
+	}
+	switch {
+	case mixed:
+		return -2 * other
+	case unlikely:
+		return -other
+	case script == scriptLatin && other > ascii+1:
+		// Latin words consist mostly of ASCII letters
+		return -other
+	}
+	return score
+}
+
+// Returns the script group of a non-ASCII letter
+func runeScript(r rune) int {
+	switch {
+	case unicode.Is(unicode.Latin, r):
+		return scriptLatin
+	case unicode.Is(unicode.Greek, r):
+		return scriptGreek
+	case unicode.Is(unicode.Cyrillic, r):
+		return scriptCyrillic
+	case unicode.Is(unicode.Arabic, r):
+		return scriptArabic
+	case unicode.Is(unicode.Hebrew, r):
+		return scriptHebrew
+	case unicode.Is(unicode.Thai, r):
+		return scriptThai
+	case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+		return scriptHan
+	case unicode.Is(unicode.Hangul, r):
+		return scriptHangul
+	}
+	return scriptUnknown
+}
+
+// Returns if the data consists of ASCII characters only
+func isASCII(data []byte) bool {
+	return bytes.IndexFunc(data, func(r rune) bool { return r >= utf8.RuneSelf }) < 0
+}
+
+// Detects the encoding of a table without code page mark, the converter is kept if the table has no non-ASCII characters
+func (file *File) detectCodePage() error {
+	converter, err := DetectEncoding(file, detectSampleRows)
+	if errors.Is(err, ErrUnknownCodePage) {
+		// Empty or ASCII-only tables keep the fallback converter or Windows-1250, see interpretCodePage
+		debugf("Encoding can not be detected, keeping the converter: %v", err)
+		return nil
+	}
+	if err != nil {
+		return newError("dbase-detect-detectcodepage-1", err)
+	}
+	file.config.Converter = converter.WithOptions(file.config.ConverterOptions)
+	return nil
+}
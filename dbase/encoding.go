@@ -1,12 +1,16 @@
 package dbase
 
 import (
-	"bytes"
+	"fmt"
+	"sync"
 	"unicode/utf8"
 
-	"io"
-
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
 	"golang.org/x/text/transform"
 )
 
@@ -17,104 +21,318 @@ type EncodingConverter interface {
 	CodePage() byte
 }
 
-type DefaultConverter struct {
-	encoding *charmap.Charmap
+// StreamConverter is implemented by converters that convert streams, e.g. memos read with MemoReader.
+// Converters without stream support convert each chunk on its own.
+type StreamConverter interface {
+	Decoder() transform.Transformer
+	Encoder() transform.Transformer
+}
+
+// LossyConverter is implemented by converters that report replaced characters, see Field.Lossy
+type LossyConverter interface {
+	EncodeLossy(in []byte) ([]byte, bool, error)
 }
 
-// Win1250Decoder translates a Windows-1250 DBF to UTF8 and back
+// ConverterOptions configures the conversion of a DefaultConverter
+type ConverterOptions struct {
+	Deterministic bool              // If true the input is always decoded, otherwise input that is valid UTF8 is returned unchanged.
+	Unencodable   UnencodablePolicy // Handling of characters the encoding can not represent.
+}
+
+// DefaultConverter converts between UTF8 and a single or double byte (DBCS) encoding
+type DefaultConverter struct {
+	encoding encoding.Encoding
+	codePage byte
+	options  ConverterOptions
+}
 
-// Decode decodes a specified encoding to byte slice to a UTF8 byte slice
+// Decode decodes a specified encoding to byte slice to a UTF8 byte slice.
+// Unless the converter is deterministic, input that is valid UTF8 is returned unchanged.
 func (c DefaultConverter) Decode(in []byte) ([]byte, error) {
-	if utf8.Valid(in) {
+	if !c.options.Deterministic && utf8.Valid(in) {
 		return in, nil
 	}
-	r := transform.NewReader(bytes.NewReader(in), c.encoding.NewDecoder())
-	data, err := io.ReadAll(r)
+	data, err := c.encoding.NewDecoder().Bytes(in)
 	if err != nil {
 		return nil, newError("dbase-encoding-decode-1", err)
 	}
 	return data, nil
 }
 
-// Decode decodes a UTF8 byte slice to the specified encoding byte slice
+// Encode encodes a UTF8 byte slice to the specified encoding byte slice.
+// Double byte encodings may return more bytes than characters.
+// Characters the encoding can not represent are handled according to the unencodable policy.
 func (c DefaultConverter) Encode(in []byte) ([]byte, error) {
-	out := make([]byte, len(in))
-	enc := c.encoding.NewEncoder()
-	nDst, _, err := enc.Transform(out, in, false)
+	data, _, err := c.EncodeLossy(in)
 	if err != nil {
 		return nil, newError("dbase-encoding-encode-1", err)
 	}
-	return out[:nDst], nil
+	return data, nil
 }
 
-// CodePageMark returns corresponding code page mark for the encoding
-func (c DefaultConverter) CodePage() byte {
-	switch c.encoding {
-	case charmap.CodePage437: // U.S. MS-DOS
-		return 0x01
-	case charmap.CodePage850: // International MS-DOS
-		return 0x02
-	case charmap.CodePage852: // Eastern European MS-DOS
-		return 0x64
-	case charmap.CodePage865: // Nordic MS-DOS
-		return 0x66
-	case charmap.CodePage866: // Russian MS-DOS
-		return 0x65
-	case charmap.Windows874: // Thai Windows
-		return 0x7C
-	case charmap.Windows1250: // Central European Windows
-		return 0xc8
-	case charmap.Windows1251: // Russian Windows
-		return 0xc9
-	case charmap.Windows1252: // Windows ANSI
-		return 0x03
-	case charmap.Windows1253: // Greek Windows
-		return 0xCB
-	case charmap.Windows1254: // Turkish Windows
-		return 0xCA
-	case charmap.Windows1255: // Hebrew Windows
-		return 0x7D
-	case charmap.Windows1256: // Arabic Windows
-		return 0x7E
-	default:
-		return 0x00
+// EncodeLossy encodes like Encode and reports if characters were replaced
+func (c DefaultConverter) EncodeLossy(in []byte) ([]byte, bool, error) {
+	handler := &unencodableHandler{encoder: c.encoding.NewEncoder(), converter: c}
+	data, _, err := transform.Bytes(handler, in)
+	if err != nil {
+		return nil, false, newError("dbase-encoding-encodelossy-1", err)
 	}
+	return data, handler.replaced > 0, nil
+}
+
+// Decoder returns a transformer decoding the encoding to UTF8
+func (c DefaultConverter) Decoder() transform.Transformer {
+	return c.encoding.NewDecoder()
+}
+
+// Encoder returns a transformer encoding UTF8 to the encoding, applying the unencodable policy
+func (c DefaultConverter) Encoder() transform.Transformer {
+	return &unencodableHandler{encoder: c.encoding.NewEncoder(), converter: c}
 }
 
-func NewDefaultConverter(encoding *charmap.Charmap) DefaultConverter {
-	return DefaultConverter{encoding: encoding}
+// CodePage returns corresponding code page mark for the encoding, 0x00 if the encoding has no mark
+func (c DefaultConverter) CodePage() byte {
+	return c.codePage
+}
+
+// WithOptions returns a copy of the converter using the options
+func (c DefaultConverter) WithOptions(options ConverterOptions) DefaultConverter {
+	c.options = options
+	return c
+}
+
+// NewDefaultConverter returns a converter for the encoding, e.g. charmap.Windows1252 or japanese.ShiftJIS.
+// The code page mark is the preferred Visual FoxPro mark of the encoding.
+func NewDefaultConverter(encoding encoding.Encoding) DefaultConverter {
+	converter := DefaultConverter{encoding: encoding}
+	for _, cp := range codePages {
+		if cp.encoding == encoding {
+			converter.codePage = cp.mark
+			break
+		}
+	}
+	return converter
 }
 
-// NewDefaultConverterFromCodePage returns a new EncodingConverter from a code page mark
+// ConverterFromCodePage returns a converter for the code page mark of the table header, Windows-1250 if the mark is not set, unknown or not supported.
+//
+// Deprecated: Use ConverterForCodePage, which reports marks that can not be interpreted.
 func ConverterFromCodePage(codePageMark byte) DefaultConverter {
-	switch codePageMark {
-	case 0x01: // U.S. MS-DOS
-		return NewDefaultConverter(charmap.CodePage437)
-	case 0x02: // International MS-DOS
-		return NewDefaultConverter(charmap.CodePage850)
-	case 0x64: // Eastern European MS-DOS
-		return NewDefaultConverter(charmap.CodePage852)
-	case 0x66: // Nordic MS-DOS
-		return NewDefaultConverter(charmap.CodePage865)
-	case 0x65: // Russian MS-DOS
-		return NewDefaultConverter(charmap.CodePage866)
-	case 0x7C: // Thai Windows
-		return NewDefaultConverter(charmap.Windows874)
-	case 0xC8: // Central European Windows
-		return NewDefaultConverter(charmap.Windows1250)
-	case 0xC9: // Russian Windows
-		return NewDefaultConverter(charmap.Windows1251)
-	case 0x03: // Windows ANSI
-		return NewDefaultConverter(charmap.Windows1252)
-	case 0xCB: // Greek Windows
-		return NewDefaultConverter(charmap.Windows1253)
-	case 0xCA: // Turkish Windows
-		return NewDefaultConverter(charmap.Windows1254)
-	case 0x7D: // Hebrew Windows
-		return NewDefaultConverter(charmap.Windows1255)
-	case 0x7E: // Arabic Windows
-		return NewDefaultConverter(charmap.Windows1256)
-	default: // Default to Central European Windows
+	converter, err := ConverterForCodePage(codePageMark)
+	if err != nil {
 		return NewDefaultConverter(charmap.Windows1250)
 	}
+	return converter
+}
+
+// ConverterForCodePage returns a converter for the code page mark of the table header.
+// Returns ErrUnknownCodePage if the mark is not set, unknown or its code page is not supported.
+func ConverterForCodePage(codePageMark byte) (DefaultConverter, error) {
+	for _, cp := range codePages {
+		if cp.mark != codePageMark {
+			continue
+		}
+		if cp.encoding == nil {
+			return DefaultConverter{}, newError("dbase-encoding-converterforcodepage-1", fmt.Errorf("%w: code page %d (%v) with mark 0x%02X is not supported", ErrUnknownCodePage, cp.number, cp.platform, codePageMark))
+		}
+		return DefaultConverter{encoding: cp.encoding, codePage: cp.mark}, nil
+	}
+	return DefaultConverter{}, newError("dbase-encoding-converterforcodepage-2", fmt.Errorf("%w: 0x%02X", ErrUnknownCodePage, codePageMark))
+}
+
+// Interprets the code page mark of the table, the fallback converter is used for unknown marks if configured.
+// Tables without code page mark are read as Windows-1250 if no fallback converter is configured,
+// with DetectCodePage the converter is replaced by the detected encoding once the table is opened, see OpenTable.
+func (file *File) interpretCodePage() error {
+	converter, err := ConverterForCodePage(file.header.CodePage)
+	if err != nil {
+		if file.config.FallbackConverter != nil {
+			debugf("Code page mark 0x%02x can not be interpreted, using the fallback converter: %v", file.header.CodePage, err)
+			file.config.Converter = file.config.FallbackConverter
+			return nil
+		}
+		if file.header.CodePage != 0x00 {
+			return newError("dbase-encoding-interpretcodepage-1", err)
+		}
+		// Tables without code page mark are read as Central European Windows
+		debugf("Code page mark is not set, using Windows-1250")
+		converter = NewDefaultConverter(charmap.Windows1250)
+	}
+	file.config.Converter = converter.WithOptions(file.config.ConverterOptions)
+	return nil
+}
+
+// A code page of the Visual FoxPro code page table
+// https://learn.microsoft.com/en-us/previous-versions/visualstudio/foxpro/8t45x02s(v=vs.80)
+type codePage struct {
+	mark     byte
+	number   int
+	platform string
+	encoding encoding.Encoding // nil if the code page is not supported
+}
+
+// All code page marks, the first mark of an encoding is used when writing.
+// Mazovia (620) and Kamenicky (895) are known but not supported.
+var codePages = []codePage{
+	{0x01, 437, "U.S. MS-DOS", charmap.CodePage437},
+	{0x02, 850, "International MS-DOS", charmap.CodePage850},
+	{0x03, 1252, "Windows ANSI", charmap.Windows1252},
+	{0x04, 10000, "Standard Macintosh", charmap.Macintosh},
+	{0x64, 852, "Eastern European MS-DOS", charmap.CodePage852},
+	{0x65, 866, "Russian MS-DOS", charmap.CodePage866},
+	{0x66, 865, "Nordic MS-DOS", charmap.CodePage865},
+	{0x67, 861, "Icelandic MS-DOS", codePage861},
+	{0x68, 895, "Kamenicky (Czech) MS-DOS", nil},
+	{0x69, 620, "Mazovia (Polish) MS-DOS", nil},
+	{0x6A, 737, "Greek MS-DOS (437G)", codePage737},
+	{0x6B, 857, "Turkish MS-DOS", codePage857},
+	{0x6C, 863, "French-Canadian MS-DOS", charmap.CodePage863},
+	{0x78, 950, "Traditional Chinese (Hong Kong SAR, Taiwan) Windows", traditionalchinese.Big5},
+	{0x79, 949, "Korean Windows", korean.EUCKR},
+	{0x7A, 936, "Chinese Simplified (PRC, Singapore) Windows", simplifiedchinese.GBK},
+	{0x7B, 932, "Japanese Windows", japanese.ShiftJIS},
+	{0x7C, 874, "Thai Windows", charmap.Windows874},
+	{0x7D, 1255, "Hebrew Windows", charmap.Windows1255},
+	{0x7E, 1256, "Arabic Windows", charmap.Windows1256},
+	{0x96, 10007, "Russian Macintosh", charmap.MacintoshCyrillic},
+	{0x97, 10029, "Eastern European Macintosh", macintoshLatin2},
+	{0x98, 10006, "Greek Macintosh", macintoshGreek},
+	{0xC8, 1250, "Central European Windows", charmap.Windows1250},
+	{0xC9, 1251, "Russian Windows", charmap.Windows1251},
+	{0xCA, 1254, "Turkish Windows", charmap.Windows1254},
+	{0xCB, 1253, "Greek Windows", charmap.Windows1253},
+	{0xCC, 1257, "Baltic Windows", charmap.Windows1257},
+	// dBase language driver marks
+	{0x08, 865, "Danish OEM", charmap.CodePage865},
+	{0x09, 437, "Dutch OEM", charmap.CodePage437},
+	{0x0A, 850, "Dutch OEM", charmap.CodePage850},
+	{0x0B, 437, "Finnish OEM", charmap.CodePage437},
+	{0x0D, 437, "French OEM", charmap.CodePage437},
+	{0x0E, 850, "French OEM", charmap.CodePage850},
+	{0x0F, 437, "German OEM", charmap.CodePage437},
+	{0x10, 850, "German OEM", charmap.CodePage850},
+	{0x11, 437, "Italian OEM", charmap.CodePage437},
+	{0x12, 850, "Italian OEM", charmap.CodePage850},
+	{0x13, 932, "Japanese Shift-JIS", japanese.ShiftJIS},
+	{0x14, 850, "Spanish OEM", charmap.CodePage850},
+	{0x15, 437, "Swedish OEM", charmap.CodePage437},
+	{0x16, 850, "Swedish OEM", charmap.CodePage850},
+	{0x17, 865, "Norwegian OEM", charmap.CodePage865},
+	{0x18, 437, "Spanish OEM", charmap.CodePage437},
+	{0x19, 437, "English OEM (Britain)", charmap.CodePage437},
+	{0x1A, 850, "English OEM (Britain)", charmap.CodePage850},
+	{0x1B, 437, "English OEM (U.S.)", charmap.CodePage437},
+	{0x1C, 863, "French OEM (Canada)", charmap.CodePage863},
+	{0x1D, 850, "French OEM", charmap.CodePage850},
+	{0x1F, 852, "Czech OEM", charmap.CodePage852},
+	{0x22, 852, "Hungarian OEM", charmap.CodePage852},
+	{0x23, 852, "Polish OEM", charmap.CodePage852},
+	{0x24, 860, "Portuguese OEM", charmap.CodePage860},
+	{0x25, 850, "Portuguese OEM", charmap.CodePage850},
+	{0x26, 866, "Russian OEM", charmap.CodePage866},
+	{0x37, 850, "English OEM (U.S.)", charmap.CodePage850},
+	{0x40, 852, "Romanian OEM", charmap.CodePage852},
+	{0x4D, 936, "Chinese GBK (PRC)", simplifiedchinese.GBK},
+	{0x4E, 949, "Korean (ANSI/OEM)", korean.EUCKR},
+	{0x4F, 950, "Chinese Big5 (Taiwan)", traditionalchinese.Big5},
+	{0x50, 874, "Thai (ANSI/OEM)", charmap.Windows874},
+	{0x57, 1252, "ANSI", charmap.Windows1252},
+	{0x58, 1252, "Western European ANSI", charmap.Windows1252},
+	{0x59, 1252, "Spanish ANSI", charmap.Windows1252},
+	{0x86, 737, "Greek OEM", codePage737},
+	{0x87, 852, "Slovenian OEM", charmap.CodePage852},
+	{0x88, 857, "Turkish OEM", codePage857},
+}
+
+// Code pages without mark, usable with NewDefaultConverter
+var (
+	CodePage864 encoding.Encoding = codePage864 // Arabic MS-DOS
+	CodePage869 encoding.Encoding = codePage869 // Modern Greek MS-DOS
+)
+
+// Single byte encoding defined by a decoding table, used for code pages not provided by golang.org/x/text
+type codePageTable struct {
+	name    string
+	decode  [256]rune // 0xFFFD for undefined bytes
+	once    sync.Once
+	encodes map[rune]byte
+}
+
+func (t *codePageTable) String() string {
+	return t.name
+}
+
+// NewDecoder returns a decoder converting the code page to UTF8
+func (t *codePageTable) NewDecoder() *encoding.Decoder {
+	return &encoding.Decoder{Transformer: codePageDecoder{table: t}}
+}
+
+// NewEncoder returns an encoder converting UTF8 to the code page
+func (t *codePageTable) NewEncoder() *encoding.Encoder {
+	t.once.Do(func() {
+		t.encodes = make(map[rune]byte, 256)
+		for i := len(t.decode) - 1; i >= 0; i-- {
+			if t.decode[i] != utf8.RuneError {
+				t.encodes[t.decode[i]] = byte(i)
+			}
+		}
+	})
+	return &encoding.Encoder{Transformer: codePageEncoder{table: t}}
+}
+
+type codePageDecoder struct {
+	transform.NopResetter
+	table *codePageTable
+}
+
+func (d codePageDecoder) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for ; nSrc < len(src); nSrc++ {
+		r := d.table.decode[src[nSrc]]
+		if nDst+utf8.RuneLen(r) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += utf8.EncodeRune(dst[nDst:], r)
+	}
+	return nDst, nSrc, nil
+}
+
+type codePageEncoder struct {
+	transform.NopResetter
+	table *codePageTable
+}
+
+func (e codePageEncoder) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for nSrc < len(src) {
+		if nDst >= len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		if !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		r, size := utf8.DecodeRune(src[nSrc:])
+		b, ok := e.table.encodes[r]
+		if !ok || r == utf8.RuneError && size == 1 {
+			return nDst, nSrc, unencodableError{r}
+		}
+		dst[nDst] = b
+		nDst++
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
+
+// Returned for runes the code page can not encode, the replacement enables encoding.ReplaceUnsupported
+type unencodableError struct {
+	r rune
+}
+
+func (e unencodableError) Error() string {
+	return fmt.Sprintf("character %q can not be represented by the encoding", e.r)
+}
+
+// Replacement returns the ASCII substitute character
+func (e unencodableError) Replacement() byte {
+	return 0x1A
 }
@@ -18,6 +18,8 @@ var (
 	// Returned when an invalid column position is used (x<1 or x>number of columns)
 	ErrInvalidPosition = errors.New("INVALID_POSITION")
 	ErrInvalidEncoding = errors.New("INVALID_ENCODING")
+	// Returned when the code page mark of a table is not set, unknown or not supported
+	ErrUnknownCodePage = errors.New("UNKNOWN_CODE_PAGE")
 )
 
 // Error is a wrapper for errors that occur in the dbase package
@@ -52,6 +54,11 @@ func (e Error) Error() string {
 	return e.err.Error()
 }
 
+// Unwrap returns the wrapped error
+func (e Error) Unwrap() error {
+	return e.err
+}
+
 // Context returns the context of the error in the dbase package
 func (e Error) Context() []string {
 	return e.context
@@ -1,8 +1,10 @@
 package dbase
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 	"time"
 )
@@ -29,10 +31,29 @@ import (
 //	N  >>  Numeric (with decimals)  >>  float64
 //	T  >>  DateTime  >>  time.Time
 //	Y  >>  Currency  >>  float64
+//	G  >>  General  >>  []byte
+//	P  >>  Picture  >>  []byte
+//	W  >>  Blob  >>  []byte
+//	+  >>  Autoincrement  >>  int32
+//	@  >>  Timestamp  >>  time.Time
+//	O  >>  Double  >>  float64
+//	0  >>  _NullFlags  >>  []byte
+//
+// dBase level 7 tables store I, +, @ and O values big endian with an inverted sign bit, empty values are nil.
+// N and F values filled with asterisks (overflow written by dBase IV and Clipper) are nil.
+// G, P and W values are read from the memo file, use ParseOLEObject to extract the object embedded in G and P values.
+// An io.Reader can be written to M, G, P and W columns, it is read until EOF.
+// If Config.ExactDecimals is set N (with decimals), F and Y columns are returned as Decimal.
 //
 // This package contains the functions to convert a dbase database entry as byte array into a row struct
 // with the columns converted into the corresponding data types.
 func (file *File) Interpret(raw []byte, column *Column) (interface{}, error) {
+	return file.interpret(raw, column, nil)
+}
+
+// Converts raw column data to the correct type for the given column
+// The null flags are the raw _NullFlags data of the row, if nil they are read from the file at the row pointer
+func (file *File) interpret(raw []byte, column *Column, nullFlags []byte) (interface{}, error) {
 	// Not all column types have been implemented because we don't use them in our DBFs
 	// Extend this function if needed
 	if len(raw) != int(column.Length) {
@@ -45,17 +66,17 @@ func (file *File) Interpret(raw []byte, column *Column) (interface{}, error) {
 	case Character:
 		// C values are stored as strings, the returned string is not trimmed
 		return file.parseCharacter(raw, column)
-	case Integer:
-		// I values are stored as numeric values
+	case Integer, Autoincrement:
+		// I and + values are stored as numeric values
 		return file.parseInteger(raw)
-	case Double:
-		// B (double) values are stored as numeric values
-		return file.parseDouble(raw)
+	case Double, DBaseDouble:
+		// B and O (double) values are stored as numeric values
+		return file.parseDouble(raw, column)
 	case Date:
 		// D values are stored as string in format YYYYMMDD, convert to time.Time
 		return file.parseDate(raw, column)
-	case DateTime:
-		// T values are stores as two 4 byte integers
+	case DateTime, Timestamp:
+		// T and @ values are stores as two 4 byte integers
 		//  integer one is the date in julian format
 		//  integer two is the number of milliseconds since midnight
 		// Above info from http://fox.wikis.com/wc.dll?Wiki~DateTime
@@ -74,18 +95,18 @@ func (file *File) Interpret(raw []byte, column *Column) (interface{}, error) {
 		return file.parseFloat(raw, column)
 	case Varchar:
 		// V values just return the raw value
-		return file.parseVarchar(raw, column)
+		return file.parseVarchar(raw, column, nullFlags)
 	case Varbinary:
 		// Q values just return the raw value
-		return file.parseVarbinary(raw, column)
+		return file.parseVarbinary(raw, column, nullFlags)
+	case Picture, General:
+		// P and G values contain the address of the OLE object in the FPT file
+		return file.parseBinary(raw, column)
 	case Blob:
-		// W values just return the raw value
-		fallthrough
-	case Picture:
-		// P values just return the raw value
-		fallthrough
-	case General:
-		// G values just return the raw value
+		// W values contain the address of the binary data in the FPT file
+		return file.parseBinary(raw, column)
+	case NullFlags:
+		// 0 values just return the raw value
 		return file.parseRaw(raw, column)
 	default:
 		return nil, newError("dbase-interpreter-datatovalue-2", fmt.Errorf("unsupported column data type: %s", string(column.DataType)))
@@ -95,18 +116,31 @@ func (file *File) Interpret(raw []byte, column *Column) (interface{}, error) {
 // Converts column data to the byte representation
 // For M values the data has to be written to the memo file
 func (file *File) GetRepresentation(field *Field, skipSpacing bool) ([]byte, error) {
+	// convert the value to the Go type of the column
+	value, err := file.coerce(field.GetValue(), field.column)
+	if err != nil {
+		return nil, newError("dbase-interpreter-getrepresentation-2", fmt.Errorf("converting value at column field: %v failed with error: %w", field.Name(), err))
+	}
 	// if value is nil, return empty byte array
-	if field.GetValue() == nil {
+	if value == nil {
+		if DataType(field.column.DataType) == Date {
+			return emptyDate(field.column), nil
+		}
 		return make([]byte, field.column.Length), nil
 	}
+	coerced := *field
+	coerced.value = value
+	// Report replaced characters of the coerced value on the field
+	defer func(original *Field) { original.lossy = coerced.lossy }(field)
+	field = &coerced
 	switch DataType(field.column.DataType) {
 	case Memo:
 		return file.getMemoRepresentation(field)
 	case Character:
 		// C values are stored as strings, the returned string is not trimmed
 		return file.getCharacterRepresentation(field, skipSpacing)
-	case Integer:
-		// I values (int32)
+	case Integer, Autoincrement:
+		// I and + values (int32)
 		return file.getIntegerRepresentation(field)
 	case Currency:
 		// Y (currency)
@@ -114,14 +148,14 @@ func (file *File) GetRepresentation(field *Field, skipSpacing bool) ([]byte, err
 	case Float:
 		// F (Float)
 		return file.getFloatRepresentation(field, skipSpacing)
-	case Double:
-		// B (double)
+	case Double, DBaseDouble:
+		// B and O (double)
 		return file.getDoubleRepresentation(field)
 	case Date:
 		// D values are stored as string in format YYYYMMDD, convert to time.Time
 		return file.getDateRepresentation(field)
-	case DateTime:
-		// T values are stores as two 4 byte integers
+	case DateTime, Timestamp:
+		// T and @ values are stores as two 4 byte integers
 		//  integer one is the date in julian format
 		//  integer two is the number of milliseconds since midnight
 		// Above info from http://fox.wikis.com/wc.dll?Wiki~DateTime
@@ -138,14 +172,11 @@ func (file *File) GetRepresentation(field *Field, skipSpacing bool) ([]byte, err
 	case Varbinary:
 		// Q values just return the raw value
 		return file.getVarbinaryRepresentation(field)
-	case Blob:
-		// W values just return the raw value
-		fallthrough
-	case Picture:
-		// P values just return the raw value
-		fallthrough
-	case General:
-		// G values just return the raw value
+	case Picture, General, Blob:
+		// P, G and W values are saved to the memo file
+		return file.getMemoRepresentation(field)
+	case NullFlags:
+		// 0 values just return the raw value
 		return file.getRawRepresentation(field)
 	default:
 		return nil, newError("dbase-interpreter-getrepresentation-1", fmt.Errorf("unsupported column data type: %s at column field: %v", field.Type(), field.Name()))
@@ -165,21 +196,51 @@ func (file *File) parseMemo(raw []byte, column *Column) (interface{}, error) {
 	return memo, nil
 }
 
-// Saves the value to the memo file and returns the address in the FPT file
+// Returns the binary data of W values and the OLE object data of G and P values (see ParseOLEObject) from the memo file
+func (file *File) parseBinary(raw []byte, column *Column) (interface{}, error) {
+	memo, _, err := file.ReadMemo(raw)
+	if err != nil {
+		return nil, newError("dbase-interpreter-parsebinary-1", fmt.Errorf("parsing binary data failed at column field: %v failed with error: %w", column.Name(), err))
+	}
+	return memo, nil
+}
+
+// Saves the value to the memo file and returns the address in the FPT file.
+// Text is encoded with the converter, io.Reader values are streamed to the memo file.
 func (file *File) getMemoRepresentation(field *Field) ([]byte, error) {
-	memo := make([]byte, 0)
+	var memo []byte
 	txt := false
-	s, sok := field.value.(string)
-	if sok {
-		memo = []byte(s)
+	switch v := field.value.(type) {
+	case string:
+		encoded, lossy, err := fromUtf8String([]byte(v), file.config.Converter)
+		if err != nil {
+			return nil, newError("dbase-interpreter-getmemorepresentation-3", fmt.Errorf("encoding text at column field: %v failed with error: %w", field.Name(), err))
+		}
+		memo = encoded
 		txt = true
-	}
-	m, ok := field.value.([]byte)
-	if ok {
-		memo = m
-		txt = false
-	}
-	if !ok && !sok {
+		field.lossy = lossy
+	case []byte:
+		memo = v
+	case OLEObject:
+		memo = v.Bytes()
+	case io.Reader:
+		if file.memoHeader == nil {
+			return nil, newError("dbase-interpreter-getmemorepresentation-6", ErrNoFPT)
+		}
+		// Readers are written as text to M columns
+		w := file.newMemoWriter(DataType(field.column.DataType) == Memo)
+		_, err := io.Copy(w, v)
+		if err != nil {
+			w.finish()
+			return nil, newError("dbase-interpreter-getmemorepresentation-4", fmt.Errorf("writing value at column field: %v failed with error: %w", field.Name(), err))
+		}
+		address, err := w.finish()
+		if err != nil {
+			return nil, newError("dbase-interpreter-getmemorepresentation-5", fmt.Errorf("writing to memo file at column field: %v failed with error: %w", field.Name(), err))
+		}
+		field.lossy = w.lossy
+		return address, nil
+	default:
 		return nil, newError("dbase-interpreter-getmemorepresentation-1", fmt.Errorf("invalid type for memo field: %T", field.value))
 	}
 	// Write the memo to the memo file
@@ -208,13 +269,20 @@ func (file *File) getCharacterRepresentation(field *Field, skipSpacing bool) ([]
 		return nil, newError("dbase-interpreter-getcharacterrepresentation-1", fmt.Errorf("invalid data type %T, expected string on column field: %v", field.value, field.Name()))
 	}
 	raw := make([]byte, field.column.Length)
-	bin, err := fromUtf8String([]byte(c), file.config.Converter)
+	bin, lossy, err := fromUtf8String([]byte(c), file.config.Converter)
 	if err != nil {
 		return nil, newError("dbase-interpreter-getcharacterrepresentation-2", fmt.Errorf("parsing from utf8 string at column field: %v failed with error %w", field.Name(), err))
 	}
+	field.lossy = lossy
 	if skipSpacing {
 		return bin, nil
 	}
+	if len(bin) > int(field.column.Length) {
+		bin, err = file.truncateCharacter(c, int(field.column.Length))
+		if err != nil {
+			return nil, newError("dbase-interpreter-getcharacterrepresentation-4", err)
+		}
+	}
 	bin = appendSpaces(bin, int(field.column.Length))
 	copy(raw, bin)
 	if len(raw) > int(field.column.Length) {
@@ -223,8 +291,31 @@ func (file *File) getCharacterRepresentation(field *Field, skipSpacing bool) ([]
 	return raw, nil
 }
 
-// Returns the value as int32
+// Encodes the string up to the length, double byte characters (DBCS) are not split
+func (file *File) truncateCharacter(s string, length int) ([]byte, error) {
+	truncated := make([]byte, 0, length)
+	for _, r := range s {
+		bin, _, err := fromUtf8String([]byte(string(r)), file.config.Converter)
+		if err != nil {
+			return nil, newError("dbase-interpreter-truncatecharacter-1", err)
+		}
+		if len(truncated)+len(bin) > length {
+			break
+		}
+		truncated = append(truncated, bin...)
+	}
+	return truncated, nil
+}
+
+// Returns the value as int32, empty dBase level 7 values are nil
 func (file *File) parseInteger(raw []byte) (interface{}, error) {
+	if file.level7() {
+		i, ok := parseSortableInt(raw)
+		if !ok {
+			return nil, nil
+		}
+		return i, nil
+	}
 	return int32(binary.LittleEndian.Uint32(raw)), nil
 }
 
@@ -233,16 +324,13 @@ func (file *File) getIntegerRepresentation(field *Field) ([]byte, error) {
 	// I values (int32)
 	i, ok := field.value.(int32)
 	if !ok {
-		f, ok := field.value.(float64)
-		if !ok {
-			return nil, newError("dbase-interpreter-getintegerrepresentation-1", fmt.Errorf("invalid data type %T, expected int32 at column field: %v", field.value, field.Name()))
-		}
-		// check for lower and uppper bounds
-		if f > 0 && f <= math.MaxInt32 {
-			i = int32(f)
-		}
+		return nil, newError("dbase-interpreter-getintegerrepresentation-1", fmt.Errorf("invalid data type %T, expected int32 at column field: %v", field.value, field.Name()))
 	}
 	raw := make([]byte, field.column.Length)
+	if file.level7() {
+		copy(raw, toSortableInt(i))
+		return raw, nil
+	}
 	bin, err := toBinary(i)
 	if err != nil {
 		return nil, newError("dbase-interpreter-getintegerrepresentation-2", fmt.Errorf("converting to binary at column field: %v failed with error: %w", field.Name(), err))
@@ -254,19 +342,30 @@ func (file *File) getIntegerRepresentation(field *Field) ([]byte, error) {
 	return raw, nil
 }
 
-// Returns the value as float64
+// Returns the value as float64 or Decimal
 func (file *File) parseCurrency(raw []byte) (interface{}, error) {
-	return float64(int64(binary.LittleEndian.Uint64(raw))) / 10000, nil
+	i := int64(binary.LittleEndian.Uint64(raw))
+	if file.config.ExactDecimals {
+		return NewDecimal(i, 4), nil
+	}
+	return float64(i) / 10000, nil
 }
 
-// Returns the float64 value as byte representation
+// Returns the float64 or Decimal value as byte representation
 func (file *File) getCurrencyRepresentation(field *Field) ([]byte, error) {
-	f, ok := field.value.(float64)
+	d, ok, err := toDecimal(field.value, 4)
 	if !ok {
-		return nil, newError("dbase-interpreter-getcurrencyrepresentation-1", fmt.Errorf("invalid data type %T, expected float64 at column field: %v", field.value, field.Name()))
+		return nil, newError("dbase-interpreter-getcurrencyrepresentation-1", fmt.Errorf("invalid data type %T, expected float64 or Decimal at column field: %v", field.value, field.Name()))
+	}
+	if err != nil {
+		return nil, newError("dbase-interpreter-getcurrencyrepresentation-4", fmt.Errorf("converting value at column field: %v failed with error: %w", field.Name(), err))
+	}
+	// Round to 4 decimal places, the unscaled value is stored as int64
+	unscaled := d.Round(4).Unscaled()
+	if !unscaled.IsInt64() {
+		return nil, newError("dbase-interpreter-getcurrencyrepresentation-5", fmt.Errorf("value %v overflows the currency range at column field: %v", d, field.Name()))
 	}
-	// Cast to int64 and multiply by 10000
-	i := int64(f * 10000)
+	i := unscaled.Int64()
 	raw := make([]byte, field.column.Length)
 	bin, err := toBinary(i)
 	if err != nil {
@@ -279,8 +378,14 @@ func (file *File) getCurrencyRepresentation(field *Field) ([]byte, error) {
 	return raw, nil
 }
 
-// Returns the value as float64
+// Returns the value as float64 or Decimal, overflow values (asterisks) are nil
 func (file *File) parseFloat(raw []byte, column *Column) (interface{}, error) {
+	if isOverflow(raw) {
+		return nil, nil
+	}
+	if file.config.ExactDecimals {
+		return file.parseDecimal(raw, column)
+	}
 	f, err := parseFloat(raw)
 	if err != nil {
 		return f, newError("dbase-interpreter-parsefloat-1", fmt.Errorf("parsing float at column field: %v failed with error: %w", column.Name(), err))
@@ -288,20 +393,45 @@ func (file *File) parseFloat(raw []byte, column *Column) (interface{}, error) {
 	return f, nil
 }
 
-// Returns the float64 value as byte representation
-func (file *File) getFloatRepresentation(field *Field, skipSpacing bool) ([]byte, error) {
-	b, ok := field.value.(float64)
-	if !ok {
-		return nil, newError("dbase-interpreter-getfloatrepresentation-1", fmt.Errorf("invalid data type %T, expected float64 at column field: %v", field.value, field.Name()))
+// Returns the value as Decimal
+func (file *File) parseDecimal(raw []byte, column *Column) (interface{}, error) {
+	trimmed := string(sanitizeString(raw))
+	if len(trimmed) == 0 {
+		return Decimal{}.Round(column.Decimals), nil
 	}
+	d, err := ParseDecimal(trimmed)
+	if err != nil {
+		return nil, newError("dbase-interpreter-parsedecimal-1", fmt.Errorf("parsing decimal at column field: %v failed with error: %w", column.Name(), err))
+	}
+	if d.Scale() < column.Decimals {
+		d = d.Round(column.Decimals)
+	}
+	return d, nil
+}
+
+// Returns the float64 or Decimal value as byte representation
+func (file *File) getFloatRepresentation(field *Field, skipSpacing bool) ([]byte, error) {
 	var bin []byte
-	if b == float64(int64(b)) {
-		// if the value is an integer, store as integer
-		bin = []byte(fmt.Sprintf("%d", int64(b)))
-	} else {
-		// if the value is a float, store as float
-		expression := fmt.Sprintf("%%.%df", field.column.Decimals)
-		bin = []byte(fmt.Sprintf(expression, field.value))
+	switch v := field.value.(type) {
+	case float64:
+		if v == float64(int64(v)) {
+			// if the value is an integer, store as integer
+			bin = []byte(fmt.Sprintf("%d", int64(v)))
+		} else {
+			// if the value is a float, store as float
+			expression := fmt.Sprintf("%%.%df", field.column.Decimals)
+			bin = []byte(fmt.Sprintf(expression, v))
+		}
+	case Decimal:
+		bin = []byte(v.Round(field.column.Decimals).String())
+	default:
+		return nil, newError("dbase-interpreter-getfloatrepresentation-1", fmt.Errorf("invalid data type %T, expected float64 or Decimal at column field: %v", field.value, field.Name()))
+	}
+	if len(bin) > int(field.column.Length) {
+		if file.config.Strict {
+			return nil, newError("dbase-interpreter-getfloatrepresentation-3", fmt.Errorf("value %s exceeds the column length %v at column field: %v", bin, field.column.Length, field.Name()))
+		}
+		bin = overflowNumber(field, bin)
 	}
 	if skipSpacing {
 		return bin, nil
@@ -309,8 +439,15 @@ func (file *File) getFloatRepresentation(field *Field, skipSpacing bool) ([]byte
 	return prependSpaces(bin, int(field.column.Length)), nil
 }
 
-// Returns the value as float64
-func (file *File) parseDouble(raw []byte) (interface{}, error) {
+// Returns the value as float64, empty dBase level 7 values are nil
+func (file *File) parseDouble(raw []byte, column *Column) (interface{}, error) {
+	if file.level7() && DataType(column.DataType) == DBaseDouble {
+		f, ok := parseSortableDouble(raw)
+		if !ok {
+			return nil, nil
+		}
+		return f, nil
+	}
 	return math.Float64frombits(binary.LittleEndian.Uint64(raw)), nil
 }
 
@@ -321,6 +458,10 @@ func (file *File) getDoubleRepresentation(field *Field) ([]byte, error) {
 		return nil, newError("dbase-interpreter-getdoublerepresentation-1", fmt.Errorf("invalid data type %T, expected float64 at column field: %v", field.value, field.Name()))
 	}
 	raw := make([]byte, field.column.Length)
+	if file.level7() && DataType(field.column.DataType) == DBaseDouble {
+		copy(raw, toSortableDouble(b))
+		return raw, nil
+	}
 	bin, err := toBinary(b)
 	if err != nil {
 		return nil, newError("dbase-interpreter-getdoublerepresentation-2", fmt.Errorf("converting to binary at column field: %v failed with error: %w", field.Name(), err))
@@ -332,32 +473,30 @@ func (file *File) getDoubleRepresentation(field *Field) ([]byte, error) {
 	return raw, nil
 }
 
-// Returns the value as time.Time
+// Returns the value as time.Time, empty dates are nil if configured
 func (file *File) parseDate(raw []byte, column *Column) (interface{}, error) {
 	// D values are stored as string in format YYYYMMDD, convert to time.Time
-	date, err := parseDate(raw)
+	date, empty, err := parseDate(raw, file.location())
 	if err != nil {
 		return date, newError("dbase-interpreter-parsedatevalue-1", fmt.Errorf("parsing to date at column field: %v failed with error: %w", column.Name(), err))
 	}
+	if empty && file.config.EmptyDateNil {
+		return nil, nil
+	}
 	return date, nil
 }
 
-// Get the time.Time value as byte representation
+// Get the time.Time value as byte representation, the zero time is stored as empty date
 func (file *File) getDateRepresentation(field *Field) ([]byte, error) {
 	d, ok := field.value.(time.Time)
 	if !ok {
-		s, ok := field.value.(string)
-		if !ok {
-			return nil, newError("dbase-interpreter-getdaterepresentation-1", fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", field.value, field.Name()))
-		}
-		t, err := time.Parse(time.RFC3339, s)
-		if err != nil {
-			return nil, newError("dbase-interpreter-getdaterepresentation-2", fmt.Errorf("parsing time failed at column field: %v failed with error: %w", field.Name(), err))
-		}
-		d = t
+		return nil, newError("dbase-interpreter-getdaterepresentation-1", fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", field.value, field.Name()))
+	}
+	if d.IsZero() {
+		return emptyDate(field.column), nil
 	}
 	raw := make([]byte, field.column.Length)
-	bin := []byte(d.Format("20060102"))
+	bin := []byte(d.In(file.location()).Format("20060102"))
 	copy(raw, bin)
 	if len(raw) != int(field.column.Length) {
 		return nil, newError("dbase-interpreter-getdaterepresentation-3", fmt.Errorf("invalid length %v bytes != %v bytes at column field: %v", len(raw), field.column.Length, field.Name()))
@@ -365,33 +504,51 @@ func (file *File) getDateRepresentation(field *Field) ([]byte, error) {
 	return raw, nil
 }
 
-// Returns the value as time.Time
+// Returns the value as time.Time, empty values are nil if configured
 func (file *File) parseDateTime(raw []byte) (interface{}, error) {
-	return parseDateTime(raw), nil
+	var t time.Time
+	var empty bool
+	if file.level7() {
+		t, empty = parseTimestamp(raw, file.location(), !file.config.DateTimeSeconds)
+	} else {
+		t, empty = parseDateTime(raw, file.location(), !file.config.DateTimeSeconds)
+	}
+	if empty && file.config.EmptyDateNil {
+		return nil, nil
+	}
+	return t, nil
 }
 
 // Get the time.Time value as byte representation consisting of 4 bytes for julian date and 4 bytes for time
 func (file *File) getDateTimeRepresentation(field *Field) ([]byte, error) {
 	t, ok := field.value.(time.Time)
 	if !ok {
-		s, ok := field.value.(string)
-		if !ok {
-			return nil, newError("dbase-interpreter-getdatetimerepresentation-1", fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", field.value, field.Name()))
-		}
-		parsedTime, err := time.Parse(time.RFC3339, s)
-		if err != nil {
-			return nil, newError("dbase-interpreter-getdatetimerepresentation-2", fmt.Errorf("parsing time failed at column field: %v failed with error: %w", field.Name(), err))
-		}
-		t = parsedTime
+		return nil, newError("dbase-interpreter-getdatetimerepresentation-1", fmt.Errorf("invalid data type %T, expected time.Time at column field: %v", field.value, field.Name()))
 	}
 	raw := make([]byte, 8)
+	if t.IsZero() {
+		// Empty values have a julian day of 0
+		return raw, nil
+	}
+	// Round to milliseconds or to whole seconds like FoxPro, a carry moves to the next day
+	if file.config.DateTimeSeconds {
+		t = t.Round(time.Second)
+	} else {
+		t = t.Round(time.Millisecond)
+	}
+	t = t.In(file.location())
 	i := ymd2jd(t.Year(), int(t.Month()), t.Day())
+	millis := t.Hour()*3600000 + t.Minute()*60000 + t.Second()*1000 + t.Nanosecond()/1000000
+	if file.level7() {
+		copy(raw[:4], toSortableInt(int32(i)))
+		copy(raw[4:], toSortableInt(int32(millis)))
+		return raw, nil
+	}
 	date, err := toBinary(uint64(i))
 	if err != nil {
 		return nil, newError("dbase-interpreter-getdatetimerepresentation-3", fmt.Errorf("time conversion at column field: %v failed with error: %w", field.Name(), err))
 	}
 	copy(raw[:4], date)
-	millis := t.Hour()*3600000 + t.Minute()*60000 + t.Second()*1000 + t.Nanosecond()/1000000
 	time, err := toBinary(uint64(millis))
 	if err != nil {
 		return nil, newError("dbase-interpreter-getdatetimerepresentation-4", fmt.Errorf("binary conversion at column field: %v failed with error: %w", field.Name(), err))
@@ -439,8 +596,11 @@ func (file *File) getRawRepresentation(field *Field) ([]byte, error) {
 	return raw, nil
 }
 
-// Returns the value as integer or float64
+// Returns the value as integer, float64 or Decimal, overflow values (asterisks) are nil
 func (file *File) parseNumeric(raw []byte, column *Column) (interface{}, error) {
+	if isOverflow(raw) {
+		return nil, nil
+	}
 	if column.Decimals == 0 {
 		i, err := parseNumericInt(raw)
 		if err != nil {
@@ -471,8 +631,18 @@ func (file *File) getNumericRepresentation(field *Field, skipSpacing bool) ([]by
 	if iok {
 		bin = []byte(fmt.Sprintf("%d", field.value))
 	}
-	if !iok && !fok {
-		return nil, newError("dbase-interpreter-getnumericrepresentation-1", fmt.Errorf("invalid data type %T, expected int64 or float64 at column field: %v", field.value, field.Name()))
+	d, dok := field.value.(Decimal)
+	if dok {
+		bin = []byte(d.Round(field.column.Decimals).String())
+	}
+	if !iok && !fok && !dok {
+		return nil, newError("dbase-interpreter-getnumericrepresentation-1", fmt.Errorf("invalid data type %T, expected int64, float64 or Decimal at column field: %v", field.value, field.Name()))
+	}
+	if len(bin) > int(field.column.Length) {
+		if file.config.Strict {
+			return nil, newError("dbase-interpreter-getnumericrepresentation-3", fmt.Errorf("value %s exceeds the column length %v at column field: %v", bin, field.column.Length, field.Name()))
+		}
+		bin = overflowNumber(field, bin)
 	}
 	if skipSpacing {
 		return bin, nil
@@ -480,8 +650,16 @@ func (file *File) getNumericRepresentation(field *Field, skipSpacing bool) ([]by
 	return prependSpaces(bin, int(field.column.Length)), nil
 }
 
-func (file *File) parseVarchar(raw []byte, column *Column) (interface{}, error) {
-	varlen, null, err := file.ReadNullFlag(uint64(file.table.rowPointer), column)
+// Returns the overflow value (asterisks) for a number exceeding the column length and reports the field as lossy.
+// Fraction digits are rounded to the decimals of the column before, so only the integer part can overflow.
+func overflowNumber(field *Field, bin []byte) []byte {
+	debugf("Value %s exceeds the column length %v at column field: %v and is written as overflow", bin, field.column.Length, field.Name())
+	field.lossy = true
+	return bytes.Repeat([]byte("*"), int(field.column.Length))
+}
+
+func (file *File) parseVarchar(raw []byte, column *Column, nullFlags []byte) (interface{}, error) {
+	varlen, null, err := file.readNullFlag(nullFlags, column)
 	if err != nil {
 		return nil, newError("dbase-interpreter-parsevarchar-1", fmt.Errorf("reading null flag at column field: %v failed with error: %w", column.Name(), err))
 	}
@@ -507,8 +685,8 @@ func (file *File) getVarcharRepresentation(field *Field) ([]byte, error) {
 	return nil, newError("dbase-interpreter-getvarcharrepresentation-1", fmt.Errorf("invalid data type %T, expected string at column field: %v", field.value, field.Name()))
 }
 
-func (file *File) parseVarbinary(raw []byte, column *Column) (interface{}, error) {
-	varlen, null, err := file.ReadNullFlag(uint64(file.table.rowPointer), column)
+func (file *File) parseVarbinary(raw []byte, column *Column, nullFlags []byte) (interface{}, error) {
+	varlen, null, err := file.readNullFlag(nullFlags, column)
 	if err != nil {
 		return nil, newError("dbase-interpreter-parsevarbinary-1", fmt.Errorf("reading null flag at column field: %v failed with error: %w", column.Name(), err))
 	}
@@ -529,3 +707,42 @@ func (file *File) getVarbinaryRepresentation(field *Field) ([]byte, error) {
 	}
 	return raw, nil
 }
+
+// Returns the variable length and null flag of a varchar or varbinary column
+// If the raw _NullFlags data of the row is passed the flags are taken from it, otherwise they are read from the file
+func (file *File) readNullFlag(nullFlags []byte, column *Column) (bool, bool, error) {
+	if nullFlags == nil {
+		return file.ReadNullFlag(uint64(file.Cursor().Pointer()), column)
+	}
+	// count what number of varchar field this field is
+	bitCount := 0
+	for _, c := range file.table.columns {
+		if c.DataType == byte(Varchar) || c.DataType == byte(Varbinary) {
+			if c == column {
+				break
+			}
+			if c.Flag == byte(NullableFlag) || c.Flag == byte(NullableFlag|BinaryFlag) {
+				bitCount += 2
+			} else {
+				bitCount++
+			}
+		}
+	}
+	if column.Flag == byte(NullableFlag) || column.Flag == byte(NullableFlag|BinaryFlag) {
+		return getNthBit(nullFlags, bitCount), getNthBit(nullFlags, bitCount+1), nil
+	}
+	return getNthBit(nullFlags, bitCount), false, nil
+}
+
+// Returns the location of date and time values
+func (file *File) location() *time.Location {
+	if file.config.Location == nil {
+		return time.UTC
+	}
+	return file.config.Location
+}
+
+// Returns the representation of an empty date, FoxPro stores blanks
+func emptyDate(column *Column) []byte {
+	return bytes.Repeat([]byte(" "), int(column.Length))
+}
@@ -7,17 +7,29 @@ import (
 
 // File is the main struct to handle a dBase file.
 // Each file type is basically a Table or a Memo file.
+//
+// Concurrency: the IO implementations read and write at explicit positions (ReadAt/WriteAt)
+// instead of seeking a shared file offset. Therefore ReadRow, ReadMemo, ReadNullFlag, Search,
+// SearchFunc and ParallelScan are safe for concurrent use.
+// Writing rows and memos is serialized by the file mutexes, but must not run concurrently
+// with reads of the same file, as the header (e.g. the rows count) is changed.
+// The internal row pointer (GoTo, Skip, Next, Prev, Row, EOF, BOF, Deleted) is the default cursor
+// and must not be used from multiple goroutines. Use NewCursor to get an independent cursor per goroutine.
+// The GenericIO falls back to locked seeking if the handles do not implement io.ReaderAt and io.WriterAt.
 type File struct {
-	config         *Config     // The config used when working with the DBF file.
-	handle         interface{} // DBase file handle.
-	relatedHandle  interface{} // Memo file handle.
-	io             IO          // The IO interface used to work with the DBF file.
-	header         *Header     // DBase file header containing relevant information.
-	memoHeader     *MemoHeader // Memo file header containing relevant information.
-	dbaseMutex     *sync.Mutex // Mutex locks for concurrent writing access to the DBF file.
-	memoMutex      *sync.Mutex // Mutex locks for concurrent writing access to the FPT file.
-	table          *Table      // Containing the columns and internal row pointer.
-	nullFlagColumn *Column     // The column containing the null flag column (if varchar or varbinary field exists).
+	config         *Config            // The config used when working with the DBF file.
+	handle         interface{}        // DBase file handle.
+	relatedHandle  interface{}        // Memo file handle.
+	io             IO                 // The IO interface used to work with the DBF file.
+	header         *Header            // DBase file header containing relevant information.
+	memoHeader     *MemoHeader        // Memo file header containing relevant information.
+	dbaseMutex     *sync.Mutex        // Mutex locks for concurrent writing access to the DBF file.
+	memoMutex      *sync.Mutex        // Mutex locks for concurrent writing access to the FPT file.
+	seekMutex      *sync.Mutex        // Mutex locks for seek based access to handles without positional reads and writes.
+	table          *Table             // Containing the columns and internal row pointer.
+	nullFlagColumn *Column            // The column containing the null flag column (if varchar or varbinary field exists).
+	descriptors    map[*Column][]byte // The raw column descriptors of dBase level 7 tables.
+	backlink       []byte             // The database container backlink of Visual FoxPro tables.
 }
 
 // IO is the interface to work with the DBF file.
@@ -47,6 +59,13 @@ type IO interface {
 	Deleted(file *File) (bool, error)
 }
 
+// MemoIO is implemented by IO implementations with positional access to the memo file.
+// MemoReader and MemoWriter stream the memo blocks with it, other implementations read and write the whole memo at once.
+type MemoIO interface {
+	ReadMemoAt(file *File, buf []byte, offset int64) (int, error)
+	WriteMemoAt(file *File, buf []byte, offset int64) (int, error)
+}
+
 // Opens a dBase database file (and the memo file if needed).
 // The config parameter is required to specify the file path, encoding, file handles (IO) and others.
 // If IO is nil, the default implementation is used depending on the OS.
@@ -54,7 +73,19 @@ func OpenTable(config *Config) (*File, error) {
 	if config.IO == nil {
 		config.IO = DefaultIO
 	}
-	return config.IO.OpenTable(config)
+	interpret := config.InterpretCodePage || config.Converter == nil
+	file, err := config.IO.OpenTable(config)
+	if err != nil {
+		return nil, err
+	}
+	if interpret && config.DetectCodePage && file.header.CodePage == 0x00 {
+		err = file.detectCodePage()
+		if err != nil {
+			file.Close()
+			return nil, newError("dbase-io-opentable-1", err)
+		}
+	}
+	return file, nil
 }
 
 // Closes all file handlers.
@@ -77,7 +108,7 @@ func (file *File) WriteHeader() error {
 	return file.defaults().io.WriteHeader(file)
 }
 
-// ReadColumns reads from DBF header, starting at pos 32, until it finds the Header row terminator END_OF_COLUMN(0x0D).
+// ReadColumns reads from DBF header, starting at pos 32 (68 for dBase level 7), until it finds the Header row terminator END_OF_COLUMN(0x0D).
 func (file *File) ReadColumns() ([]*Column, *Column, error) {
 	return file.defaults().io.ReadColumns(file)
 }
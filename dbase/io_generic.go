@@ -3,6 +3,7 @@ package dbase
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -29,6 +30,7 @@ func (g GenericIO) OpenTable(config *Config) (*File, error) {
 		relatedHandle: g.RelatedHandle,
 		dbaseMutex:    &sync.Mutex{},
 		memoMutex:     &sync.Mutex{},
+		seekMutex:     &sync.Mutex{},
 	}
 	err := file.ReadHeader()
 	if err != nil {
@@ -53,7 +55,10 @@ func (g GenericIO) OpenTable(config *Config) (*File, error) {
 			debugf("No encoding converter defined, falling back to default (interpreting)")
 		}
 		debugf("Interpreting code page mark...")
-		file.config.Converter = ConverterFromCodePage(file.header.CodePage)
+		err = file.interpretCodePage()
+		if err != nil {
+			return nil, newError("dbase-io-generic-opentable-4", err)
+		}
 		debugf("Code page: 0x%02x => interpreted: 0x%02x", file.header.CodePage, file.config.Converter.CodePage())
 	}
 	// Check if the code page mark is matchin the converter
@@ -118,16 +123,12 @@ func (g GenericIO) ReadHeader(file *File) error {
 	if err != nil {
 		return newError("dbase-io-generic-readheader-1", err)
 	}
-	_, err = handle.Seek(0, 0)
-	if err != nil {
-		return newError("dbase-io-generic-generic-readheader-2", err)
-	}
+	h := &Header{}
 	b := make([]byte, 30)
-	n, err := handle.Read(b)
-	if err != nil {
+	n, err := g.readAt(file, handle, b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return newError("dbase-io-generic-generic-readheader-3", err)
 	}
-	h := &Header{}
 	// LittleEndian - Integers in table files are stored with the least significant byte first.
 	err = binary.Read(bytes.NewReader(b[:n]), binary.LittleEndian, h)
 	if err != nil {
@@ -137,36 +138,27 @@ func (g GenericIO) ReadHeader(file *File) error {
 	return nil
 }
 
-func (g GenericIO) WriteHeader(file *File) error {
+func (g GenericIO) WriteHeader(file *File) (err error) {
 	debugf("Writing header...")
 	handle, err := g.getHandle(file)
 	if err != nil {
 		return newError("dbase-io-generic-writeheader-1", err)
 	}
-	_, err = handle.Seek(0, 0)
-	if err != nil {
-		return newError("dbase-io-generic-generic-writeheader-2", err)
-	}
 	// Change the last modification date to the current date
 	file.header.Year = uint8(time.Now().Year() - 2000)
 	file.header.Month = uint8(time.Now().Month())
 	file.header.Day = uint8(time.Now().Day())
-
+	debugf("Writing header: %+v", file.header)
+	// Write the header at the beginning of the file
 	buf := new(bytes.Buffer)
 	err = binary.Write(buf, binary.LittleEndian, file.header)
 	if err != nil {
 		return newError("dbase-io-generic-generic-writeheader-3", err)
 	}
-
-	b, err := handle.Write(buf.Bytes())
+	_, err = g.writeAt(file, handle, buf.Bytes(), 0)
 	if err != nil {
 		return newError("dbase-io-generic-generic-writeheader-4", err)
 	}
-
-	if b != len(buf.Bytes()) {
-		return newError("dbase-io-generic-generic-writeheader-5", fmt.Errorf("wrote %d bytes, expected %d", b, len(buf.Bytes())))
-	}
-
 	return nil
 }
 
@@ -176,93 +168,30 @@ func (g GenericIO) ReadColumns(file *File) ([]*Column, *Column, error) {
 	if err != nil {
 		return nil, nil, newError("dbase-io-generic-readcolumns-1", err)
 	}
-	var nullFlag *Column
-	columns := make([]*Column, 0)
-	offset := int64(32)
-	b := make([]byte, 1)
-	for {
-		// Check if we are at 0x0D by reading one byte ahead
-		if _, err := handle.Seek(offset, 0); err != nil {
-			return nil, nil, newError("dbase-io-generic-generic-readcolumns-2", err)
-		}
-		if _, err := handle.Read(b); err != nil {
-			return nil, nil, newError("dbase-io-generic-generic-readcolumns-3", err)
-		}
-		if Marker(b[0]) == ColumnEnd {
-			break
-		}
-		// Position back one byte and read the column
-		if _, err := handle.Seek(-1, 1); err != nil {
-			return nil, nil, newError("dbase-io-generic-generic-readcolumns-4", err)
-		}
-		buf := make([]byte, 33)
-		n, err := handle.Read(buf)
-		if err != nil {
-			return nil, nil, newError("dbase-io-generic-generic-readcolumns-5", err)
-		}
-		column := &Column{}
-		err = binary.Read(bytes.NewReader(buf[:n]), binary.LittleEndian, column)
-		if err != nil {
-			return nil, nil, newError("dbase-io-generic-generic-readcolumns-6", err)
-		}
-		if column.Name() == "_NullFlags" {
-			debugf("Found null flag column: %s", column.Name())
-			nullFlag = column
-			offset += 32
-			continue
-		}
-		debugf("Found column %v of type %v at offset: %d", column.Name(), column.Type(), offset)
-		columns = append(columns, column)
-		offset += 32
+	columns, nullFlag, err := file.readColumns(func(b []byte, offset int64) (int, error) {
+		return g.readAt(file, handle, b, offset)
+	})
+	if err != nil {
+		return nil, nil, newError("dbase-io-generic-readcolumns-2", err)
 	}
 	return columns, nullFlag, nil
 }
 
-func (g GenericIO) WriteColumns(file *File) error {
+func (g GenericIO) WriteColumns(file *File) (err error) {
 	debugf("Writing columns...")
 	handle, err := g.getHandle(file)
 	if err != nil {
 		return newError("dbase-io-generic-writecolumns-1", err)
 	}
-	// Seek to the beginning of the file
-	_, err = handle.Seek(32, 0)
+	// Write the columns followed by the terminator
+	buf, err := file.encodeColumns()
 	if err != nil {
-		return newError("dbase-io-generic-generic-writecolumns-2", err)
-	}
-	// Write the columns
-	buf := new(bytes.Buffer)
-	for _, column := range file.table.columns {
-		debugf("Writing column: %+v", column)
-		err = binary.Write(buf, binary.LittleEndian, column)
-		if err != nil {
-			return newError("dbase-io-generic-generic-writecolumns-3", err)
-		}
-	}
-	if file.nullFlagColumn != nil {
-		debugf("Writing null flag column: %s", file.nullFlagColumn.Name())
-		err = binary.Write(buf, binary.LittleEndian, file.nullFlagColumn)
-		if err != nil {
-			return newError("dbase-io-generic-generic-writecolumns-4", err)
-		}
+		return newError("dbase-io-generic-generic-writecolumns-3", err)
 	}
-	_, err = handle.Write(buf.Bytes())
+	_, err = g.writeAt(file, handle, buf, file.columnsOffset())
 	if err != nil {
 		return newError("dbase-io-generic-generic-writecolumns-5", err)
 	}
-	// Write the column terminator
-	_, err = handle.Write([]byte{byte(ColumnEnd)})
-	if err != nil {
-		return newError("dbase-io-generic-generic-writecolumns-6", err)
-	}
-	// Write null till the end of the header
-	pos := file.header.FirstRow - uint16(len(file.table.columns)*32) - 33
-	if file.nullFlagColumn != nil {
-		pos -= 32
-	}
-	_, err = handle.Write(make([]byte, pos))
-	if err != nil {
-		return newError("dbase-io-generic-writecolumns-7", err)
-	}
 	return nil
 }
 
@@ -273,12 +202,9 @@ func (g GenericIO) ReadMemoHeader(file *File) error {
 		return newError("dbase-io-generic-readmemoheader-1", err)
 	}
 	h := &MemoHeader{}
-	if _, err := relatedHandle.Seek(0, 0); err != nil {
-		return newError("dbase-io-generic-readmemoheader-2", err)
-	}
 	b := make([]byte, 8)
-	n, err := relatedHandle.Read(b)
-	if err != nil {
+	n, err := g.readAt(file, relatedHandle, b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return newError("dbase-io-generic-readmemoheader-3", err)
 	}
 	err = binary.Read(bytes.NewReader(b[:n]), binary.BigEndian, h)
@@ -290,58 +216,44 @@ func (g GenericIO) ReadMemoHeader(file *File) error {
 	return nil
 }
 
-func (g GenericIO) WriteMemoHeader(file *File, size int) error {
+func (g GenericIO) WriteMemoHeader(file *File, size int) (err error) {
 	relatedHandle, err := g.getRelatedHandle(file)
 	if err != nil {
 		return newError("dbase-io-generic-writeheader-1", err)
 	}
 	debugf("Writing memo header...")
-	// Seek to the beginning of the file
-	_, err = relatedHandle.Seek(0, 0)
-	if err != nil {
-		return newError("dbase-io-generic-writememoheader-3", err)
-	}
 	// Calculate the next free block
 	file.memoHeader.NextFree += uint32(size)
-	// Write the memo header
-	buf := make([]byte, 8)
+	// Write the memo header followed by null till the end of the header
+	buf := make([]byte, 512)
 	binary.BigEndian.PutUint32(buf[:4], file.memoHeader.NextFree)
 	binary.BigEndian.PutUint16(buf[6:8], file.memoHeader.BlockSize)
 	debugf("Writing memo header - next free: %d, block size: %d", file.memoHeader.NextFree, file.memoHeader.BlockSize)
-	_, err = relatedHandle.Write(buf)
+	_, err = g.writeAt(file, relatedHandle, buf, 0)
 	if err != nil {
 		return newError("dbase-io-generic-writememoheader-5", err)
 	}
-	// Write null till end of header
-	_, err = relatedHandle.Write(make([]byte, 512-8))
-	if err != nil {
-		return newError("dbase-io-generic-writememoheader-6", err)
-	}
 	return nil
 }
 
-func (g GenericIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
+func (g GenericIO) ReadMemo(file *File, blockdata []byte) ([]byte, bool, error) {
 	relatedHandle, err := g.getRelatedHandle(file)
 	if err != nil {
 		return nil, false, newError("dbase-io-generic-readmemo-1", err)
 	}
 	// Determine the block number
-	block := binary.LittleEndian.Uint32(address)
+	block := binary.LittleEndian.Uint32(blockdata)
 	if block == 0 {
 		return []byte{}, false, nil
 	}
+	// The position in the file is blocknumber*blocksize
 	position := int64(file.memoHeader.BlockSize) * int64(block)
 	debugf("Reading memo block %d at position %d", block, position)
-	// The position in the file is blocknumber*blocksize
-	_, err = relatedHandle.Seek(position, 0)
-	if err != nil {
-		return nil, false, newError("dbase-io-generic-readmemo-2", err)
-	}
 	// Read the memo block header, instead of reading into a struct using binary.Read we just read the two
 	// uints in one buffer and then convert, this saves seconds for large DBF files with many memo columns
 	// as it avoids using the reflection in binary.Read
 	hbuf := make([]byte, 8)
-	_, err = relatedHandle.Read(hbuf)
+	_, err = g.readAt(file, relatedHandle, hbuf, position)
 	if err != nil {
 		return nil, false, newError("dbase-io-generic-readmemo-3", err)
 	}
@@ -354,8 +266,8 @@ func (g GenericIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
 	}
 	// Now read the actual data
 	buf := make([]byte, leng)
-	read, err := relatedHandle.Read(buf)
-	if err != nil {
+	read, err := g.readAt(file, relatedHandle, buf, position+8)
+	if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 		return buf, false, newError("dbase-io-generic-readmemo-4", err)
 	}
 	if read != int(leng) {
@@ -379,8 +291,8 @@ func (g GenericIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	}
 	// Get the block position
 	blockPosition := file.memoHeader.NextFree
-	blocks := length / int(file.memoHeader.BlockSize)
-	if length%int(file.memoHeader.BlockSize) > 0 {
+	blocks := (length + 8) / int(file.memoHeader.BlockSize)
+	if (length+8)%int(file.memoHeader.BlockSize) > 0 {
 		blocks++
 	}
 	// Write the memo header
@@ -402,13 +314,8 @@ func (g GenericIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	data = append(data, raw...)
 	position := int64(blockPosition) * int64(file.memoHeader.BlockSize)
 	debugf("Writing memo block %d at position %d", blockPosition, position)
-	// Seek to new the next free block
-	_, err = relatedHandle.Seek(position, 0)
-	if err != nil {
-		return nil, newError("dbase-io-generic-writememo-4", err)
-	}
-	// Write the memo data
-	_, err = relatedHandle.Write(data)
+	// Write the memo data to the next free block
+	_, err = g.writeAt(file, relatedHandle, data, position)
 	if err != nil {
 		return nil, newError("dbase-io-generic-writememo-5", err)
 	}
@@ -420,51 +327,57 @@ func (g GenericIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	return address, nil
 }
 
-func (g GenericIO) ReadNullFlag(file *File, position uint64, column *Column) (bool, bool, error) {
+func (g GenericIO) ReadMemoAt(file *File, buf []byte, offset int64) (int, error) {
+	relatedHandle, err := g.getRelatedHandle(file)
+	if err != nil {
+		return 0, newError("dbase-io-generic-readmemoat-1", err)
+	}
+	n, err := g.readAt(file, relatedHandle, buf, offset)
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(buf)) {
+		return n, newError("dbase-io-generic-readmemoat-2", err)
+	}
+	return n, nil
+}
+
+func (g GenericIO) WriteMemoAt(file *File, buf []byte, offset int64) (int, error) {
+	relatedHandle, err := g.getRelatedHandle(file)
+	if err != nil {
+		return 0, newError("dbase-io-generic-writememoat-1", err)
+	}
+	n, err := g.writeAt(file, relatedHandle, buf, offset)
+	if err != nil {
+		return n, newError("dbase-io-generic-writememoat-2", err)
+	}
+	return n, nil
+}
+
+func (g GenericIO) ReadNullFlag(file *File, rowPosition uint64, column *Column) (bool, bool, error) {
 	handle, err := g.getHandle(file)
 	if err != nil {
 		return false, false, newError("dbase-io-generic-readnullflag-1", err)
 	}
 	if file.nullFlagColumn == nil {
-		return false, false, newError("dbase-io-generic-readnullflag-2", fmt.Errorf("null flag column missing"))
+		return false, false, newError("dbase-io-generic-readnullflag-2", fmt.Errorf("null flag column not found"))
 	}
 	if column.DataType != byte(Varchar) && column.DataType != byte(Varbinary) {
-		return false, false, newError("dbase-io-generic-readnullflag-3", fmt.Errorf("column not a varchar or varbinary"))
-	}
-	// count what number of varchar field this field is
-	bitCount := 0
-	for _, c := range file.table.columns {
-		if c.DataType == byte(Varchar) || c.DataType == byte(Varbinary) {
-			if c == column {
-				break
-			}
-			if c.Flag == byte(NullableFlag) || c.Flag == byte(NullableFlag|BinaryFlag) {
-				bitCount += 2
-			} else {
-				bitCount++
-			}
-		}
+		return false, false, newError("dbase-io-generic-readnullflag-3", fmt.Errorf("column is not a varchar or varbinary column"))
 	}
 	// Read the null flag field
-	position = uint64(file.header.FirstRow) + position*uint64(file.header.RowLength) + uint64(file.nullFlagColumn.Position)
-	_, err = handle.Seek(int64(position), 0)
-	if err != nil {
-		return false, false, newError("dbase-io-generic-readnullflag-4", err)
-	}
+	position := uint64(file.header.FirstRow) + rowPosition*uint64(file.header.RowLength) + uint64(file.nullFlagColumn.Position)
 	buf := make([]byte, file.nullFlagColumn.Length)
-	n, err := handle.Read(buf)
-	if err != nil {
+	n, err := g.readAt(file, handle, buf, int64(position))
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(buf)) {
 		return false, false, newError("dbase-io-generic-readnullflag-5", err)
 	}
 	if n != int(file.nullFlagColumn.Length) {
 		return false, false, newError("dbase-io-generic-readnullflag-6", fmt.Errorf("read %d bytes, expected %d", n, file.nullFlagColumn.Length))
 	}
-	if column.Flag == byte(NullableFlag) || column.Flag == byte(NullableFlag|BinaryFlag) {
-		debugf("Read _NullFlag for column %s => varlength: %v - null: %v", column.Name(), getNthBit(buf, bitCount), getNthBit(buf, bitCount+1))
-		return getNthBit(buf, bitCount), getNthBit(buf, bitCount+1), nil
+	varlength, null, err := file.readNullFlag(buf, column)
+	if err != nil {
+		return false, false, newError("dbase-io-generic-readnullflag-7", err)
 	}
-	debugf("Read _NullFlag for column %s => varlength: %v ", column.Name(), getNthBit(buf, bitCount))
-	return getNthBit(buf, bitCount), false, nil
+	debugf("Read _NullFlag for column %s => varlength: %v - null: %v", column.Name(), varlength, null)
+	return varlength, null, nil
 }
 
 func (g GenericIO) ReadRow(file *File, position uint32) ([]byte, error) {
@@ -478,12 +391,8 @@ func (g GenericIO) ReadRow(file *File, position uint32) ([]byte, error) {
 	pos := int64(file.header.FirstRow) + (int64(position) * int64(file.header.RowLength))
 	debugf("Reading row: %d at offset: %v", position, pos)
 	buf := make([]byte, file.header.RowLength)
-	_, err = handle.Seek(pos, 0)
-	if err != nil {
-		return buf, newError("dbase-io-generic-readrow-3", err)
-	}
-	read, err := handle.Read(buf)
-	if err != nil {
+	read, err := g.readAt(file, handle, buf, pos)
+	if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 		return buf, newError("dbase-io-generic-readrow-4", err)
 	}
 	if read != int(file.header.RowLength) {
@@ -492,7 +401,7 @@ func (g GenericIO) ReadRow(file *File, position uint32) ([]byte, error) {
 	return buf, nil
 }
 
-func (g GenericIO) WriteRow(file *File, row *Row) error {
+func (g GenericIO) WriteRow(file *File, row *Row) (err error) {
 	debugf("Writing row: %d ...", row.Position)
 	row.handle.dbaseMutex.Lock()
 	defer row.handle.dbaseMutex.Unlock()
@@ -516,13 +425,7 @@ func (g GenericIO) WriteRow(file *File, row *Row) error {
 		return newError("dbase-io-generic-writerow-3", err)
 	}
 	debugf("Writing row: %d at offset: %v", row.Position, position)
-	// Seek to the correct position
-	_, err = handle.Seek(position, 0)
-	if err != nil {
-		return newError("dbase-io-generic-writerow-4", err)
-	}
-	// Write the row
-	_, err = handle.Write(r)
+	_, err = g.writeAt(file, handle, r, position)
 	if err != nil {
 		return newError("dbase-io-generic-writerow-5", err)
 	}
@@ -538,7 +441,7 @@ func (g GenericIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 		return nil, newError("dbase-io-generic-search-2", err)
 	}
 	debugf("Searching for value: %v in field: %s", field.GetValue(), field.column.Name())
-	// convert the value to bytes
+	// convert the value to a string
 	val, err := file.GetRepresentation(field, !exactMatch)
 	if err != nil {
 		return nil, newError("dbase-io-generic-search-3", err)
@@ -546,18 +449,14 @@ func (g GenericIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 	// Search for the value
 	rows := make([]*Row, 0)
 	position := uint64(file.header.FirstRow)
+	buf := make([]byte, field.column.Length)
 	for i := uint32(0); i < file.header.RowsCount; i++ {
 		// Read the field value
 		p := int64(position) + int64(field.column.Position)
 		debugf("Searching at position: %d", p)
-		_, err := handle.Seek(p, 0)
 		position += uint64(file.header.RowLength)
-		if err != nil {
-			continue
-		}
-		buf := make([]byte, field.column.Length)
-		read, err := handle.Read(buf)
-		if err != nil {
+		read, err := g.readAt(file, handle, buf, p)
+		if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 			continue
 		}
 		if read != int(field.column.Length) {
@@ -565,12 +464,12 @@ func (g GenericIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 		}
 		// Check if the value matches
 		if bytes.Contains(buf, val) {
-			debugf("Found matching field at position: %d - Record %v position: %v ", p, i+1, p-int64(field.column.Position))
-			err := file.GoTo(i)
+			debugf("Found matching row %v at position: %d", i, p-int64(field.column.Position))
+			data, err := file.ReadRow(i)
 			if err != nil {
 				continue
 			}
-			row, err := file.Row()
+			row, err := file.bytesToRow(data, i, file.table.projection)
 			if err != nil {
 				continue
 			}
@@ -581,49 +480,49 @@ func (g GenericIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 }
 
 func (g GenericIO) GoTo(file *File, row uint32) error {
-	if row > file.header.RowsCount {
-		file.table.rowPointer = file.header.RowsCount
-		return newError("dbase-io-generic-goto-1", fmt.Errorf("%w, go to %v > %v", ErrEOF, row, file.header.RowsCount))
-	}
-	debugf("Going to row: %d", row)
-	file.table.rowPointer = row
-	return nil
+	return file.Cursor().GoTo(row)
 }
 
 func (g GenericIO) Skip(file *File, offset int64) {
-	newval := int64(file.table.rowPointer) + offset
-	if newval >= int64(file.header.RowsCount) {
-		file.table.rowPointer = file.header.RowsCount
-	}
-	if newval < 0 {
-		file.table.rowPointer = 0
-	}
-	file.table.rowPointer = uint32(newval)
-	debugf("Skipping %d row/s, new position: %d", offset, file.table.rowPointer)
+	file.Cursor().Skip(offset)
 }
 
 func (g GenericIO) Deleted(file *File) (bool, error) {
-	if file.table.rowPointer >= file.header.RowsCount {
-		return false, newError("dbase-io-generic-deleted-1", ErrEOF)
+	return file.Cursor().Deleted()
+}
+
+// readAt reads from the handle at the given offset.
+// If the handle implements io.ReaderAt a positional read is used, otherwise seeking and reading is locked.
+func (g GenericIO) readAt(file *File, handle io.ReadWriteSeeker, buf []byte, offset int64) (int, error) {
+	if reader, ok := handle.(io.ReaderAt); ok {
+		return reader.ReadAt(buf, offset)
 	}
-	handle, ok := file.handle.(io.ReadWriteSeeker)
-	if !ok {
-		return false, newError("dbase-io-generic-deleted-2", fmt.Errorf("handle is of wrong type %T expected io.ReadWriteSeeker", file.handle))
+	file.seekMutex.Lock()
+	defer file.seekMutex.Unlock()
+	_, err := handle.Seek(offset, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(handle, buf)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, io.EOF
 	}
+	return n, err
+}
 
-	_, err := handle.Seek(int64(file.header.FirstRow)+(int64(file.table.rowPointer)*int64(file.header.RowLength)), 0)
-	if err != nil {
-		return false, newError("dbase-io-generic-deleted-3", err)
+// writeAt writes to the handle at the given offset.
+// If the handle implements io.WriterAt a positional write is used, otherwise seeking and writing is locked.
+func (g GenericIO) writeAt(file *File, handle io.ReadWriteSeeker, buf []byte, offset int64) (int, error) {
+	if writer, ok := handle.(io.WriterAt); ok {
+		return writer.WriteAt(buf, offset)
 	}
-	buf := make([]byte, 1)
-	read, err := handle.Read(buf)
+	file.seekMutex.Lock()
+	defer file.seekMutex.Unlock()
+	_, err := handle.Seek(offset, io.SeekStart)
 	if err != nil {
-		return false, newError("dbase-io-generic-deleted-4", err)
-	}
-	if read != 1 {
-		return false, newError("dbase-io-generic-deleted-5", ErrIncomplete)
+		return 0, err
 	}
-	return Marker(buf[0]) == Deleted, nil
+	return handle.Write(buf)
 }
 
 func (g GenericIO) getHandle(file *File) (io.ReadWriteSeeker, error) {
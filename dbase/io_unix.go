@@ -6,7 +6,9 @@ package dbase
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -52,6 +54,7 @@ func (u UnixIO) OpenTable(config *Config) (*File, error) {
 		handle:     handle,
 		dbaseMutex: &sync.Mutex{},
 		memoMutex:  &sync.Mutex{},
+		seekMutex:  &sync.Mutex{},
 	}
 	err = file.ReadHeader()
 	if err != nil {
@@ -76,7 +79,10 @@ func (u UnixIO) OpenTable(config *Config) (*File, error) {
 			debugf("No encoding converter defined, falling back to default (interpreting)")
 		}
 		debugf("Interpreting code page mark...")
-		file.config.Converter = ConverterFromCodePage(file.header.CodePage)
+		err = file.interpretCodePage()
+		if err != nil {
+			return nil, newError("dbase-io-unix-opentable-5", err)
+		}
 		debugf("Code page: 0x%02x => interpreted: 0x%02x", file.header.CodePage, file.config.Converter.CodePage())
 	}
 	// Check if the code page mark is matchin the converter
@@ -174,12 +180,9 @@ func (u UnixIO) ReadHeader(file *File) error {
 		return newError("dbase-io-unix-readheader-1", err)
 	}
 	h := &Header{}
-	if _, err := handle.Seek(0, 0); err != nil {
-		return newError("dbase-io-unix-readheader-2", err)
-	}
 	b := make([]byte, 30)
-	n, err := handle.Read(b)
-	if err != nil {
+	n, err := handle.ReadAt(b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return newError("dbase-io-unix-readheader-3", err)
 	}
 	// LittleEndian - Integers in table files are stored with the least significant byte first.
@@ -197,23 +200,18 @@ func (u UnixIO) WriteHeader(file *File) (err error) {
 	if err != nil {
 		return newError("dbase-io-unix-writeheader-1", err)
 	}
-	// Seek to the beginning of the file
-	_, err = handle.Seek(0, 0)
-	if err != nil {
-		return newError("dbase-io-unix-writeheader-4", err)
-	}
 	// Change the last modification date to the current date
 	file.header.Year = uint8(time.Now().Year() - 2000)
 	file.header.Month = uint8(time.Now().Month())
 	file.header.Day = uint8(time.Now().Day())
 	debugf("Writing header: %+v", file.header)
-	// Write the header
+	// Write the header at the beginning of the file
 	buf := new(bytes.Buffer)
 	err = binary.Write(buf, binary.LittleEndian, file.header)
 	if err != nil {
 		return newError("dbase-io-unix-writeheader-5", err)
 	}
-	_, err = handle.Write(buf.Bytes())
+	_, err = handle.WriteAt(buf.Bytes(), 0)
 	if err != nil {
 		return newError("dbase-io-unix-writeheader-6", err)
 	}
@@ -226,44 +224,11 @@ func (u UnixIO) ReadColumns(file *File) ([]*Column, *Column, error) {
 	if err != nil {
 		return nil, nil, newError("dbase-io-unix-readcolumns-1", err)
 	}
-	var nullFlag *Column
-	columns := make([]*Column, 0)
-	offset := int64(32)
-	b := make([]byte, 1)
-	for {
-		// Check if we are at 0x0D by reading one byte ahead
-		if _, err := handle.Seek(offset, 0); err != nil {
-			return nil, nil, newError("dbase-io-unix-readcolumninfos-1", err)
-		}
-		if _, err := handle.Read(b); err != nil {
-			return nil, nil, newError("dbase-io-unix-readcolumninfos-2", err)
-		}
-		if b[0] == byte(ColumnEnd) {
-			break
-		}
-		// Position back one byte and read the column
-		if _, err := handle.Seek(-1, 1); err != nil {
-			return nil, nil, newError("dbase-io-unix-readcolumninfos-3", err)
-		}
-		buf := make([]byte, 33)
-		n, err := handle.Read(buf)
-		if err != nil {
-			return nil, nil, newError("dbase-io-unix-readcolumninfos-4", err)
-		}
-		column := &Column{}
-		err = binary.Read(bytes.NewReader(buf[:n]), binary.LittleEndian, column)
-		if err != nil {
-			return nil, nil, newError("dbase-io-unix-readcolumninfos-5", err)
-		}
-		if column.Name() == "_NullFlags" {
-			debugf("Found null flag column: %s", column.Name())
-			nullFlag = column
-			offset += 32
-			continue
-		}
-		debugf("Found column %v of type %v at offset: %d", column.Name(), column.Type(), offset)
-		columns = append(columns, column)
-		offset += 32
+	columns, nullFlag, err := file.readColumns(func(b []byte, offset int64) (int, error) {
+		return handle.ReadAt(b, offset)
+	})
+	if err != nil {
+		return nil, nil, newError("dbase-io-unix-readcolumns-2", err)
 	}
 	return columns, nullFlag, nil
 }
@@ -274,45 +239,15 @@ func (u UnixIO) WriteColumns(file *File) (err error) {
 	if err != nil {
 		return newError("dbase-io-unix-writecolumns-1", err)
 	}
-	// Seek to the beginning of the file
-	_, err = handle.Seek(32, 0)
+	// Write the columns followed by the terminator
+	buf, err := file.encodeColumns()
 	if err != nil {
-		return newError("dbase-io-unix-writecolumns-3", err)
-	}
-	// Write the columns
-	buf := new(bytes.Buffer)
-	for _, column := range file.table.columns {
-		debugf("Writing column: %+v", column)
-		err = binary.Write(buf, binary.LittleEndian, column)
-		if err != nil {
-			return newError("dbase-io-unix-writecolumns-4", err)
-		}
-	}
-	if file.nullFlagColumn != nil {
-		debugf("Writing null flag column: %s", file.nullFlagColumn.Name())
-		err = binary.Write(buf, binary.LittleEndian, file.nullFlagColumn)
-		if err != nil {
-			return newError("dbase-io-unix-writecolumns-5", err)
-		}
+		return newError("dbase-io-unix-writecolumns-4", err)
 	}
-	_, err = handle.Write(buf.Bytes())
+	_, err = handle.WriteAt(buf, file.columnsOffset())
 	if err != nil {
 		return newError("dbase-io-unix-writecolumns-6", err)
 	}
-	// Write the column terminator
-	_, err = handle.Write([]byte{byte(ColumnEnd)})
-	if err != nil {
-		return newError("dbase-io-unix-writecolumns-7", err)
-	}
-	// Write null till the end of the header
-	pos := file.header.FirstRow - uint16(len(file.table.columns)*32) - 33
-	if file.nullFlagColumn != nil {
-		pos -= 32
-	}
-	_, err = handle.Write(make([]byte, pos))
-	if err != nil {
-		return newError("dbase-io-unix-writecolumns-8", err)
-	}
 	return nil
 }
 
@@ -327,42 +262,22 @@ func (u UnixIO) ReadNullFlag(file *File, rowPosition uint64, column *Column) (bo
 	if column.DataType != byte(Varchar) && column.DataType != byte(Varbinary) {
 		return false, false, newError("dbase-io-unix-readnullflag-3", fmt.Errorf("column is not a varchar or varbinary column"))
 	}
-	// count what number of varchar field this field is
-	bitCount := 0
-	for _, c := range file.table.columns {
-		if c.DataType == byte(Varchar) || c.DataType == byte(Varbinary) {
-			if c == column {
-				break
-			}
-			if c.Flag == byte(NullableFlag) || c.Flag == byte(NullableFlag|BinaryFlag) {
-				bitCount += 2
-			} else {
-				bitCount++
-			}
-		}
-	}
 	// Read the null flag field
 	position := uint64(file.header.FirstRow) + rowPosition*uint64(file.header.RowLength) + uint64(file.nullFlagColumn.Position)
-	_, err = handle.Seek(int64(position), 0)
-	if err != nil {
-		return false, false, newError("dbase-io-unix-readnullflag-1", err)
-	}
 	buf := make([]byte, file.nullFlagColumn.Length)
-	n, err := handle.Read(buf)
-	if err != nil {
+	n, err := handle.ReadAt(buf, int64(position))
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(buf)) {
 		return false, false, newError("dbase-io-unix-readnullflag-2", err)
 	}
 	if n != int(file.nullFlagColumn.Length) {
 		return false, false, newError("dbase-io-unix-readnullflag-3", fmt.Errorf("read %d bytes, expected %d", n, file.nullFlagColumn.Length))
 	}
-
-	if column.Flag == byte(NullableFlag) || column.Flag == byte(NullableFlag|BinaryFlag) {
-		debugf("Read _NullFlag for column %s => varlength: %v - null: %v", column.Name(), getNthBit(buf, bitCount), getNthBit(buf, bitCount+1))
-		return getNthBit(buf, bitCount), getNthBit(buf, bitCount+1), nil
+	varlength, null, err := file.readNullFlag(buf, column)
+	if err != nil {
+		return false, false, newError("dbase-io-unix-readnullflag-4", err)
 	}
-
-	debugf("Read _NullFlag for column %s => varlength: %v", column.Name(), getNthBit(buf, bitCount))
-	return getNthBit(buf, bitCount), false, nil
+	debugf("Read _NullFlag for column %s => varlength: %v - null: %v", column.Name(), varlength, null)
+	return varlength, null, nil
 }
 
 func (u UnixIO) ReadMemoHeader(file *File) error {
@@ -372,12 +287,9 @@ func (u UnixIO) ReadMemoHeader(file *File) error {
 		return newError("dbase-io-unix-close-1", err)
 	}
 	h := &MemoHeader{}
-	if _, err := relatedHandle.Seek(0, 0); err != nil {
-		return newError("dbase-io-unix-readmemoheader-2", err)
-	}
 	b := make([]byte, 8)
-	n, err := relatedHandle.Read(b)
-	if err != nil {
+	n, err := relatedHandle.ReadAt(b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return newError("dbase-io-unix-readmemoheader-3", err)
 	}
 	err = binary.Read(bytes.NewReader(b[:n]), binary.BigEndian, h)
@@ -397,18 +309,17 @@ func (u UnixIO) ReadMemo(file *File, blockdata []byte) ([]byte, bool, error) {
 	}
 	// Determine the block number
 	block := binary.LittleEndian.Uint32(blockdata)
+	if block == 0 {
+		return []byte{}, false, nil
+	}
 	// The position in the file is blocknumber*blocksize
 	position := int64(file.memoHeader.BlockSize) * int64(block)
 	debugf("Reading memo block %d at position %d", block, position)
-	_, err = relatedHandle.Seek(position, 0)
-	if err != nil {
-		return nil, false, newError("dbase-io-unix-readmemo-2", err)
-	}
 	// Read the memo block header, instead of reading into a struct using binary.Read we just read the two
 	// uints in one buffer and then convert, this saves seconds for large DBF files with many memo columns
 	// as it avoids using the reflection in binary.Read
 	hbuf := make([]byte, 8)
-	_, err = relatedHandle.Read(hbuf)
+	_, err = relatedHandle.ReadAt(hbuf, position)
 	if err != nil {
 		return nil, false, newError("dbase-io-unix-readmemo-3", err)
 	}
@@ -421,8 +332,8 @@ func (u UnixIO) ReadMemo(file *File, blockdata []byte) ([]byte, bool, error) {
 	}
 	// Now read the actual data
 	buf := make([]byte, leng)
-	read, err := relatedHandle.Read(buf)
-	if err != nil {
+	read, err := relatedHandle.ReadAt(buf, position+8)
+	if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 		return buf, false, newError("dbase-io-unix-readmemo-4", err)
 	}
 	if read != int(leng) {
@@ -446,8 +357,8 @@ func (u UnixIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]byte
 	}
 	// Get the block position
 	blockPosition := file.memoHeader.NextFree
-	blocks := length / int(file.memoHeader.BlockSize)
-	if length%int(file.memoHeader.BlockSize) > 0 {
+	blocks := (length + 8) / int(file.memoHeader.BlockSize)
+	if (length+8)%int(file.memoHeader.BlockSize) > 0 {
 		blocks++
 	}
 	// Write the memo header
@@ -469,13 +380,8 @@ func (u UnixIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]byte
 	data = append(data, raw...)
 	position := int64(blockPosition) * int64(file.memoHeader.BlockSize)
 	debugf("Writing memo block %d at position %d", blockPosition, position)
-	// Seek to new the next free block
-	_, err = relatedHandle.Seek(position, 0)
-	if err != nil {
-		return nil, newError("dbase-io-unix-writememo-5", err)
-	}
-	// Write the memo data
-	_, err = relatedHandle.Write(data)
+	// Write the memo data to the next free block
+	_, err = relatedHandle.WriteAt(data, position)
 	if err != nil {
 		return nil, newError("dbase-io-unix-writememo-6", err)
 	}
@@ -487,33 +393,47 @@ func (u UnixIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]byte
 	return address, nil
 }
 
+func (u UnixIO) ReadMemoAt(file *File, buf []byte, offset int64) (int, error) {
+	relatedHandle, err := u.getRelatedHandle(file)
+	if err != nil {
+		return 0, newError("dbase-io-unix-readmemoat-1", err)
+	}
+	n, err := relatedHandle.ReadAt(buf, offset)
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(buf)) {
+		return n, newError("dbase-io-unix-readmemoat-2", err)
+	}
+	return n, nil
+}
+
+func (u UnixIO) WriteMemoAt(file *File, buf []byte, offset int64) (int, error) {
+	relatedHandle, err := u.getRelatedHandle(file)
+	if err != nil {
+		return 0, newError("dbase-io-unix-writememoat-1", err)
+	}
+	n, err := relatedHandle.WriteAt(buf, offset)
+	if err != nil {
+		return n, newError("dbase-io-unix-writememoat-2", err)
+	}
+	return n, nil
+}
+
 func (u UnixIO) WriteMemoHeader(file *File, size int) (err error) {
 	relatedHandle, err := u.getRelatedHandle(file)
 	if err != nil {
 		return newError("dbase-io-unix-writememoheader-1", err)
 	}
 	debugf("Writing memo header...")
-	// Seek to the beginning of the file
-	_, err = relatedHandle.Seek(0, 0)
-	if err != nil {
-		return newError("dbase-io-unix-writememoheader-4", err)
-	}
 	// Calculate the next free block
 	file.memoHeader.NextFree += uint32(size)
-	// Write the memo header
-	buf := make([]byte, 8)
+	// Write the memo header followed by null till the end of the header
+	buf := make([]byte, 512)
 	binary.BigEndian.PutUint32(buf[:4], file.memoHeader.NextFree)
 	binary.BigEndian.PutUint16(buf[6:8], file.memoHeader.BlockSize)
 	debugf("Writing memo header - next free: %d, block size: %d", file.memoHeader.NextFree, file.memoHeader.BlockSize)
-	_, err = relatedHandle.Write(buf)
+	_, err = relatedHandle.WriteAt(buf, 0)
 	if err != nil {
 		return newError("dbase-io-unix-writememoheader-5", err)
 	}
-	// Write null till end of header
-	_, err = relatedHandle.Write(make([]byte, 512-8))
-	if err != nil {
-		return newError("dbase-io-unix-writememoheader-6", err)
-	}
 	return nil
 }
 
@@ -528,12 +448,8 @@ func (u UnixIO) ReadRow(file *File, position uint32) ([]byte, error) {
 	pos := int64(file.header.FirstRow) + (int64(position) * int64(file.header.RowLength))
 	debugf("Reading row: %d at offset: %v", position, pos)
 	buf := make([]byte, file.header.RowLength)
-	_, err = handle.Seek(pos, 0)
-	if err != nil {
-		return buf, newError("dbase-io-unix-readrow-3", err)
-	}
-	read, err := handle.Read(buf)
-	if err != nil {
+	read, err := handle.ReadAt(buf, pos)
+	if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 		return buf, newError("dbase-io-unix-readrow-4", err)
 	}
 	if read != int(file.header.RowLength) {
@@ -566,13 +482,7 @@ func (u UnixIO) WriteRow(file *File, row *Row) (err error) {
 		return newError("dbase-io-unix-writerow-3", err)
 	}
 	debugf("Writing row: %d at offset: %v", row.Position, position)
-	// Seek to the correct position
-	_, err = handle.Seek(position, 0)
-	if err != nil {
-		return newError("dbase-io-unix-writerow-6", err)
-	}
-	// Write the row
-	_, err = handle.Write(r)
+	_, err = handle.WriteAt(r, position)
 	if err != nil {
 		return newError("dbase-io-unix-writerow-7", err)
 	}
@@ -596,18 +506,14 @@ func (u UnixIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, error
 	// Search for the value
 	rows := make([]*Row, 0)
 	position := uint64(file.header.FirstRow)
+	buf := make([]byte, field.column.Length)
 	for i := uint32(0); i < file.header.RowsCount; i++ {
 		// Read the field value
 		p := int64(position) + int64(field.column.Position)
 		debugf("Searching at position: %d", p)
-		_, err := handle.Seek(p, 0)
 		position += uint64(file.header.RowLength)
-		if err != nil {
-			continue
-		}
-		buf := make([]byte, field.column.Length)
-		read, err := handle.Read(buf)
-		if err != nil {
+		read, err := handle.ReadAt(buf, p)
+		if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 			continue
 		}
 		if read != int(field.column.Length) {
@@ -616,11 +522,11 @@ func (u UnixIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, error
 		// Check if the value matches
 		if bytes.Contains(buf, val) {
 			debugf("Found matching row %v at position: %d", i, p-int64(field.column.Position))
-			err := file.GoTo(i)
+			data, err := file.ReadRow(i)
 			if err != nil {
 				continue
 			}
-			row, err := file.Row()
+			row, err := file.bytesToRow(data, i, file.table.projection)
 			if err != nil {
 				continue
 			}
@@ -631,48 +537,15 @@ func (u UnixIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, error
 }
 
 func (u UnixIO) GoTo(file *File, row uint32) error {
-	if row > file.header.RowsCount {
-		file.table.rowPointer = file.header.RowsCount
-		return newError("dbase-io-unix-goto-1", fmt.Errorf("%w, go to %v > %v", ErrEOF, row, file.header.RowsCount))
-	}
-	debugf("Going to row: %d", row)
-	file.table.rowPointer = row
-	return nil
+	return file.Cursor().GoTo(row)
 }
 
 func (u UnixIO) Skip(file *File, offset int64) {
-	newval := int64(file.table.rowPointer) + offset
-	if newval >= int64(file.header.RowsCount) {
-		file.table.rowPointer = file.header.RowsCount
-	}
-	if newval < 0 {
-		file.table.rowPointer = 0
-	}
-	file.table.rowPointer = uint32(newval)
-	debugf("Skipping %d row/s, new position: %d", offset, file.table.rowPointer)
+	file.Cursor().Skip(offset)
 }
 
 func (u UnixIO) Deleted(file *File) (bool, error) {
-	if file.table.rowPointer >= file.header.RowsCount {
-		return false, newError("dbase-io-unix-deleted-1", ErrEOF)
-	}
-	handle, err := u.getHandle(file)
-	if err != nil {
-		return false, newError("dbase-io-unix-deleted-2", err)
-	}
-	_, err = handle.Seek(int64(file.header.FirstRow)+(int64(file.table.rowPointer)*int64(file.header.RowLength)), 0)
-	if err != nil {
-		return false, newError("dbase-io-unix-deleted-3", err)
-	}
-	buf := make([]byte, 1)
-	read, err := handle.Read(buf)
-	if err != nil {
-		return false, newError("dbase-io-unix-deleted-4", err)
-	}
-	if read != 1 {
-		return false, newError("dbase-io-unix-deleted-5", ErrIncomplete)
-	}
-	return buf[0] == byte(Deleted), nil
+	return file.Cursor().Deleted()
 }
 
 func _findFile(name string) (string, error) {
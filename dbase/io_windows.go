@@ -6,7 +6,9 @@ package dbase
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -54,6 +56,7 @@ func (w WindowsIO) OpenTable(config *Config) (*File, error) {
 		handle:     &fd,
 		dbaseMutex: &sync.Mutex{},
 		memoMutex:  &sync.Mutex{},
+		seekMutex:  &sync.Mutex{},
 	}
 	err = file.ReadHeader()
 	if err != nil {
@@ -78,7 +81,10 @@ func (w WindowsIO) OpenTable(config *Config) (*File, error) {
 			debugf("No encoding converter defined, falling back to default (interpreting)")
 		}
 		debugf("Interpreting code page mark...")
-		file.config.Converter = ConverterFromCodePage(file.header.CodePage)
+		err = file.interpretCodePage()
+		if err != nil {
+			return nil, newError("dbase-io-windows-opentable-5", err)
+		}
 		debugf("Code page: 0x%02x => interpreted: 0x%02x", file.header.CodePage, file.config.Converter.CodePage())
 	}
 	// Check if the code page mark is matchin the converter
@@ -181,12 +187,9 @@ func (w WindowsIO) ReadHeader(file *File) error {
 		return newError("dbase-io-windows-readheader-1", err)
 	}
 	h := &Header{}
-	if _, err := windows.Seek(*handle, 0, 0); err != nil {
-		return newError("dbase-io-windows-readheader-2", err)
-	}
 	b := make([]byte, 30)
-	n, err := windows.Read(*handle, b)
-	if err != nil {
+	n, err := w.readAt(handle, b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return newError("dbase-io-windows-readheader-3", err)
 	}
 	// LittleEndian - Integers in table files are stored with the least significant byte first.
@@ -223,23 +226,18 @@ func (w WindowsIO) WriteHeader(file *File) (err error) {
 			}
 		}()
 	}
-	// Seek to the beginning of the file
-	_, err = windows.Seek(*handle, 0, 0)
-	if err != nil {
-		return newError("dbase-io-windows-writeheader-4", err)
-	}
 	// Change the last modification date to the current date
 	file.header.Year = uint8(time.Now().Year() - 2000)
 	file.header.Month = uint8(time.Now().Month())
 	file.header.Day = uint8(time.Now().Day())
 	debugf("Writing header: %+v", file.header)
-	// Write the header
+	// Write the header at the beginning of the file
 	buf := new(bytes.Buffer)
 	err = binary.Write(buf, binary.LittleEndian, file.header)
 	if err != nil {
 		return newError("dbase-io-windows-writeheader-5", err)
 	}
-	_, err = windows.Write(*handle, buf.Bytes())
+	_, err = w.writeAt(handle, buf.Bytes(), 0)
 	if err != nil {
 		return newError("dbase-io-windows-writeheader-6", err)
 	}
@@ -252,44 +250,11 @@ func (w WindowsIO) ReadColumns(file *File) ([]*Column, *Column, error) {
 	if err != nil {
 		return nil, nil, newError("dbase-io-windows-readcolumns-1", err)
 	}
-	var nullFlag *Column
-	columns := make([]*Column, 0)
-	offset := int64(32)
-	b := make([]byte, 1)
-	for {
-		// Check if we are at 0x0D by reading one byte ahead
-		if _, err := windows.Seek(*handle, offset, 0); err != nil {
-			return nil, nil, newError("dbase-io-windows-readcolumns-1", err)
-		}
-		if _, err := windows.Read(*handle, b); err != nil {
-			return nil, nil, newError("dbase-io-windows-readcolumns-2", err)
-		}
-		if Marker(b[0]) == ColumnEnd {
-			break
-		}
-		// Position back one byte and read the column
-		if _, err := windows.Seek(*handle, -1, 1); err != nil {
-			return nil, nil, newError("dbase-io-windows-readcolumns-3", err)
-		}
-		buf := make([]byte, 33)
-		n, err := windows.Read(*handle, buf)
-		if err != nil {
-			return nil, nil, newError("dbase-io-windows-readcolumns-4", err)
-		}
-		column := &Column{}
-		err = binary.Read(bytes.NewReader(buf[:n]), binary.LittleEndian, column)
-		if err != nil {
-			return nil, nil, newError("dbase-io-windows-readcolumns-5", err)
-		}
-		if column.Name() == "_NullFlags" {
-			debugf("Found null flag column: %s", column.Name())
-			nullFlag = column
-			offset += 32
-			continue
-		}
-		debugf("Found column %v of type %v at offset: %d", column.Name(), column.Type(), offset)
-		columns = append(columns, column)
-		offset += 32
+	columns, nullFlag, err := file.readColumns(func(b []byte, offset int64) (int, error) {
+		return w.readAt(handle, b, offset)
+	})
+	if err != nil {
+		return nil, nil, newError("dbase-io-windows-readcolumns-2", err)
 	}
 	return columns, nullFlag, nil
 }
@@ -301,7 +266,7 @@ func (w WindowsIO) WriteColumns(file *File) (err error) {
 		return newError("dbase-io-windows-writecolumns-1", err)
 	}
 	// Lock the block we are writing to
-	position := uint32(32)
+	position := uint32(file.columnsOffset())
 	o := &windows.Overlapped{
 		Offset:     position,
 		OffsetHigh: position + uint32(file.header.FirstRow),
@@ -319,93 +284,45 @@ func (w WindowsIO) WriteColumns(file *File) (err error) {
 			}
 		}()
 	}
-	// Seek to the beginning of the file
-	_, err = windows.Seek(*handle, 32, 0)
+	// Write the columns followed by the terminator
+	buf, err := file.encodeColumns()
 	if err != nil {
-		return newError("dbase-io-windows-writecolumns-4", err)
+		return newError("dbase-io-windows-writecolumns-5", err)
 	}
-	// Write the columns
-	buf := new(bytes.Buffer)
-	for _, column := range file.table.columns {
-		debugf("Writing column: %+v", column)
-		err = binary.Write(buf, binary.LittleEndian, column)
-		if err != nil {
-			return newError("dbase-io-windows-writecolumns-5", err)
-		}
-	}
-	if file.nullFlagColumn != nil {
-		debugf("Writing null flag column: %s", file.nullFlagColumn.Name())
-		err = binary.Write(buf, binary.LittleEndian, file.nullFlagColumn)
-		if err != nil {
-			return newError("dbase-io-windows-writecolumns-6", err)
-		}
-	}
-	_, err = windows.Write(*handle, buf.Bytes())
+	_, err = w.writeAt(handle, buf, file.columnsOffset())
 	if err != nil {
 		return newError("dbase-io-windows-writecolumns-7", err)
 	}
-	// Write the column terminator
-	_, err = windows.Write(*handle, []byte{byte(ColumnEnd)})
-	if err != nil {
-		return newError("dbase-io-windows-writecolumns-8", err)
-	}
-	// Write null till the end of the header
-	pos := file.header.FirstRow - uint16(len(file.table.columns)*32) - 33
-	if file.nullFlagColumn != nil {
-		pos -= 32
-	}
-	_, err = windows.Write(*handle, make([]byte, pos))
-	if err != nil {
-		return newError("dbase-io-windows-writecolumns-9", err)
-	}
 	return nil
 }
 
-func (w WindowsIO) ReadNullFlag(file *File, position uint64, column *Column) (bool, bool, error) {
+func (w WindowsIO) ReadNullFlag(file *File, rowPosition uint64, column *Column) (bool, bool, error) {
 	handle, err := w.getHandle(file)
 	if err != nil {
 		return false, false, newError("dbase-io-windows-readnullflag-1", err)
 	}
 	if file.nullFlagColumn == nil {
-		return false, false, newError("dbase-io-windows-readnullflag-2", fmt.Errorf("null flag column is nil"))
+		return false, false, newError("dbase-io-windows-readnullflag-2", fmt.Errorf("null flag column not found"))
 	}
 	if column.DataType != byte(Varchar) && column.DataType != byte(Varbinary) {
-		return false, false, newError("dbase-io-windows-readnullflag-3", fmt.Errorf("column is not of type varchar or varbinary"))
-	}
-	// count what number of varchar field this field is
-	bitCount := 0
-	for _, c := range file.table.columns {
-		if c.DataType == byte(Varchar) || c.DataType == byte(Varbinary) {
-			if c == column {
-				break
-			}
-			if c.Flag == byte(NullableFlag) || c.Flag == byte(NullableFlag|BinaryFlag) {
-				bitCount += 2
-			} else {
-				bitCount++
-			}
-		}
+		return false, false, newError("dbase-io-windows-readnullflag-3", fmt.Errorf("column is not a varchar or varbinary column"))
 	}
 	// Read the null flag field
-	pos := uint64(file.header.FirstRow) + position*uint64(file.header.RowLength) + uint64(file.nullFlagColumn.Position)
-	_, err = windows.Seek(*handle, int64(pos), 0)
-	if err != nil {
-		return false, false, newError("dbase-io-windows-readnullflag-1", err)
-	}
+	position := uint64(file.header.FirstRow) + rowPosition*uint64(file.header.RowLength) + uint64(file.nullFlagColumn.Position)
 	buf := make([]byte, file.nullFlagColumn.Length)
-	n, err := windows.Read(*handle, buf)
-	if err != nil {
+	n, err := w.readAt(handle, buf, int64(position))
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(buf)) {
 		return false, false, newError("dbase-io-windows-readnullflag-2", err)
 	}
 	if n != int(file.nullFlagColumn.Length) {
 		return false, false, newError("dbase-io-windows-readnullflag-3", fmt.Errorf("read %d bytes, expected %d", n, file.nullFlagColumn.Length))
 	}
-	if column.Flag == byte(NullableFlag) || column.Flag == byte(NullableFlag|BinaryFlag) {
-		debugf("Read _NullFlag for column %s => varlength: %v - null: %v", column.Name(), getNthBit(buf, bitCount), getNthBit(buf, bitCount+1))
-		return getNthBit(buf, bitCount), getNthBit(buf, bitCount+1), nil
+	varlength, null, err := file.readNullFlag(buf, column)
+	if err != nil {
+		return false, false, newError("dbase-io-windows-readnullflag-4", err)
 	}
-	debugf("Read _NullFlag for column %s => varlength: %v ", column.Name(), getNthBit(buf, bitCount))
-	return getNthBit(buf, bitCount), false, nil
+	debugf("Read _NullFlag for column %s => varlength: %v - null: %v", column.Name(), varlength, null)
+	return varlength, null, nil
 }
 
 func (w WindowsIO) ReadMemoHeader(file *File) error {
@@ -414,15 +331,12 @@ func (w WindowsIO) ReadMemoHeader(file *File) error {
 	if err != nil {
 		return newError("dbase-io-windows-readmemoheader-1", err)
 	}
-	if _, err := windows.Seek(*relatedHandle, 0, 0); err != nil {
-		return newError("dbase-io-windows-readmemoheader-2", err)
-	}
+	h := &MemoHeader{}
 	b := make([]byte, 8)
-	n, err := windows.Read(*relatedHandle, b)
-	if err != nil {
+	n, err := w.readAt(relatedHandle, b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return newError("dbase-io-windows-readmemoheader-3", err)
 	}
-	h := &MemoHeader{}
 	err = binary.Read(bytes.NewReader(b[:n]), binary.BigEndian, h)
 	if err != nil {
 		return newError("dbase-io-windows-readmemoheader-4", err)
@@ -433,7 +347,7 @@ func (w WindowsIO) ReadMemoHeader(file *File) error {
 	return nil
 }
 
-func (w WindowsIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
+func (w WindowsIO) ReadMemo(file *File, blockdata []byte) ([]byte, bool, error) {
 	if file.relatedHandle == nil {
 		return nil, false, newError("dbase-io-windows-readmemo-1", ErrNoFPT)
 	}
@@ -442,22 +356,18 @@ func (w WindowsIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
 		return nil, false, newError("dbase-io-windows-readmemo-2", err)
 	}
 	// Determine the block number
-	block := binary.LittleEndian.Uint32(address)
+	block := binary.LittleEndian.Uint32(blockdata)
 	if block == 0 {
 		return []byte{}, false, nil
 	}
+	// The position in the file is blocknumber*blocksize
 	position := int64(file.memoHeader.BlockSize) * int64(block)
 	debugf("Reading memo block %d at position %d", block, position)
-	// The position in the file is blocknumber*blocksize
-	_, err = windows.Seek(*relatedHandle, position, 0)
-	if err != nil {
-		return nil, false, newError("dbase-io-windows-readmemo-3", err)
-	}
 	// Read the memo block header, instead of reading into a struct using binary.Read we just read the two
 	// uints in one buffer and then convert, this saves seconds for large DBF files with many memo columns
 	// as it avoids using the reflection in binary.Read
 	hbuf := make([]byte, 8)
-	_, err = windows.Read(*relatedHandle, hbuf)
+	_, err = w.readAt(relatedHandle, hbuf, position)
 	if err != nil {
 		return nil, false, newError("dbase-io-windows-readmemo-4", err)
 	}
@@ -470,8 +380,8 @@ func (w WindowsIO) ReadMemo(file *File, address []byte) ([]byte, bool, error) {
 	}
 	// Now read the actual data
 	buf := make([]byte, leng)
-	read, err := windows.Read(*relatedHandle, buf)
-	if err != nil {
+	read, err := w.readAt(relatedHandle, buf, position+8)
+	if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 		return buf, false, newError("dbase-io-windows-readmemo-5", err)
 	}
 	if read != int(leng) {
@@ -495,9 +405,9 @@ func (w WindowsIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	}
 	blocks := 1
 	blockPosition := file.memoHeader.NextFree
-	if length > 0 && file.memoHeader.BlockSize > 0 {
-		blocks = length / int(file.memoHeader.BlockSize)
-		if length%int(file.memoHeader.BlockSize) > 0 {
+	if file.memoHeader.BlockSize > 0 {
+		blocks = (length + 8) / int(file.memoHeader.BlockSize)
+		if (length+8)%int(file.memoHeader.BlockSize) > 0 {
 			blocks++
 		}
 	}
@@ -537,13 +447,8 @@ func (w WindowsIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	}
 	position := int64(blockPosition) * int64(file.memoHeader.BlockSize)
 	debugf("Writing memo block %d at position %d", blockPosition, position)
-	// Seek to new the next free block
-	_, err = windows.Seek(*relatedHandle, position, 0)
-	if err != nil {
-		return nil, newError("dbase-io-windows-writememo-5", err)
-	}
-	// Write the memo data
-	_, err = windows.Write(*relatedHandle, data)
+	// Write the memo data to the next free block
+	_, err = w.writeAt(relatedHandle, data, position)
 	if err != nil {
 		return nil, newError("dbase-io-windows-writememo-6", err)
 	}
@@ -555,6 +460,30 @@ func (w WindowsIO) WriteMemo(file *File, raw []byte, text bool, length int) ([]b
 	return address, nil
 }
 
+func (w WindowsIO) ReadMemoAt(file *File, buf []byte, offset int64) (int, error) {
+	relatedHandle, err := w.getRelatedHandle(file)
+	if err != nil {
+		return 0, newError("dbase-io-windows-readmemoat-1", err)
+	}
+	n, err := w.readAt(relatedHandle, buf, offset)
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(buf)) {
+		return n, newError("dbase-io-windows-readmemoat-2", err)
+	}
+	return n, nil
+}
+
+func (w WindowsIO) WriteMemoAt(file *File, buf []byte, offset int64) (int, error) {
+	relatedHandle, err := w.getRelatedHandle(file)
+	if err != nil {
+		return 0, newError("dbase-io-windows-writememoat-1", err)
+	}
+	n, err := w.writeAt(relatedHandle, buf, offset)
+	if err != nil {
+		return n, newError("dbase-io-windows-writememoat-2", err)
+	}
+	return n, nil
+}
+
 func (w WindowsIO) WriteMemoHeader(file *File, size int) (err error) {
 	relatedHandle, err := w.getRelatedHandle(file)
 	if err != nil {
@@ -578,27 +507,17 @@ func (w WindowsIO) WriteMemoHeader(file *File, size int) (err error) {
 			}
 		}()
 	}
-	// Seek to the beginning of the file
-	_, err = windows.Seek(*relatedHandle, 0, 0)
-	if err != nil {
-		return newError("dbase-io-windows-writememoheader-4", err)
-	}
 	// Calculate the next free block
 	file.memoHeader.NextFree += uint32(size)
-	// Write the memo header
-	buf := make([]byte, 8)
+	// Write the memo header followed by null till the end of the header
+	buf := make([]byte, 512)
 	binary.BigEndian.PutUint32(buf[:4], file.memoHeader.NextFree)
 	binary.BigEndian.PutUint16(buf[6:8], file.memoHeader.BlockSize)
 	debugf("Writing memo header - next free: %d, block size: %d", file.memoHeader.NextFree, file.memoHeader.BlockSize)
-	_, err = windows.Write(*relatedHandle, buf)
+	_, err = w.writeAt(relatedHandle, buf, 0)
 	if err != nil {
 		return newError("dbase-io-windows-writememoheader-5", err)
 	}
-	// Write null till end of header
-	_, err = windows.Write(*relatedHandle, make([]byte, 512-8))
-	if err != nil {
-		return newError("dbase-io-windows-writememoheader-6", err)
-	}
 	return nil
 }
 
@@ -613,12 +532,8 @@ func (w WindowsIO) ReadRow(file *File, position uint32) ([]byte, error) {
 	pos := int64(file.header.FirstRow) + (int64(position) * int64(file.header.RowLength))
 	debugf("Reading row: %d at offset: %v", position, pos)
 	buf := make([]byte, file.header.RowLength)
-	_, err = windows.Seek(*handle, pos, 0)
-	if err != nil {
-		return buf, newError("dbase-io-windows-readrow-3", err)
-	}
-	read, err := windows.Read(*handle, buf)
-	if err != nil {
+	read, err := w.readAt(handle, buf, pos)
+	if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 		return buf, newError("dbase-io-windows-readrow-4", err)
 	}
 	if read != int(file.header.RowLength) {
@@ -669,13 +584,7 @@ func (w WindowsIO) WriteRow(file *File, row *Row) (err error) {
 		}()
 	}
 	debugf("Writing row: %d at offset: %v", row.Position, position)
-	// Seek to the correct position
-	_, err = windows.Seek(*handle, position, 0)
-	if err != nil {
-		return newError("dbase-io-windows-writerow-6", err)
-	}
-	// Write the row
-	_, err = windows.Write(*handle, r)
+	_, err = w.writeAt(handle, r, position)
 	if err != nil {
 		return newError("dbase-io-windows-writerow-7", err)
 	}
@@ -691,7 +600,7 @@ func (w WindowsIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 		return nil, newError("dbase-io-windows-search-2", err)
 	}
 	debugf("Searching for value: %v in field: %s", field.GetValue(), field.column.Name())
-	// convert the value to bytes
+	// convert the value to a string
 	val, err := file.GetRepresentation(field, !exactMatch)
 	if err != nil {
 		return nil, newError("dbase-io-windows-search-3", err)
@@ -699,18 +608,14 @@ func (w WindowsIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 	// Search for the value
 	rows := make([]*Row, 0)
 	position := uint64(file.header.FirstRow)
+	buf := make([]byte, field.column.Length)
 	for i := uint32(0); i < file.header.RowsCount; i++ {
 		// Read the field value
 		p := int64(position) + int64(field.column.Position)
 		debugf("Searching at position: %d", p)
-		_, err := windows.Seek(*handle, p, 0)
 		position += uint64(file.header.RowLength)
-		if err != nil {
-			continue
-		}
-		buf := make([]byte, field.column.Length)
-		read, err := windows.Read(*handle, buf)
-		if err != nil {
+		read, err := w.readAt(handle, buf, p)
+		if err != nil && !(errors.Is(err, io.EOF) && read == len(buf)) {
 			continue
 		}
 		if read != int(field.column.Length) {
@@ -718,12 +623,12 @@ func (w WindowsIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 		}
 		// Check if the value matches
 		if bytes.Contains(buf, val) {
-			debugf("Found matching field at position: %d - Record %v position: %v ", p, i+1, p-int64(field.column.Position))
-			err := file.GoTo(i)
+			debugf("Found matching row %v at position: %d", i, p-int64(field.column.Position))
+			data, err := file.ReadRow(i)
 			if err != nil {
 				continue
 			}
-			row, err := file.Row()
+			row, err := file.bytesToRow(data, i, file.table.projection)
 			if err != nil {
 				continue
 			}
@@ -734,48 +639,52 @@ func (w WindowsIO) Search(file *File, field *Field, exactMatch bool) ([]*Row, er
 }
 
 func (w WindowsIO) GoTo(file *File, row uint32) error {
-	if row > file.header.RowsCount {
-		file.table.rowPointer = file.header.RowsCount
-		return newError("dbase-io-windows-goto-1", fmt.Errorf("%w, go to %v > %v", ErrEOF, row, file.header.RowsCount))
-	}
-	debugf("Going to row: %d", row)
-	file.table.rowPointer = row
-	return nil
+	return file.Cursor().GoTo(row)
 }
 
 func (w WindowsIO) Skip(file *File, offset int64) {
-	newval := int64(file.table.rowPointer) + offset
-	if newval >= int64(file.header.RowsCount) {
-		file.table.rowPointer = file.header.RowsCount
-	}
-	if newval < 0 {
-		file.table.rowPointer = 0
-	}
-	file.table.rowPointer = uint32(newval)
-	debugf("Skipping %d row/s, new position: %d", offset, file.table.rowPointer)
+	file.Cursor().Skip(offset)
 }
 
 func (w WindowsIO) Deleted(file *File) (bool, error) {
-	if file.table.rowPointer >= file.header.RowsCount {
-		return false, newError("dbase-io-windows-deleted-1", ErrEOF)
+	return file.Cursor().Deleted()
+}
+
+// readAt reads from the handle at the given offset without using the shared file pointer
+func (w WindowsIO) readAt(handle *windows.Handle, buf []byte, offset int64) (int, error) {
+	o := &windows.Overlapped{
+		Offset:     uint32(offset),
+		OffsetHigh: uint32(offset >> 32),
 	}
-	handle, err := w.getHandle(file)
+	var done uint32
+	err := windows.ReadFile(*handle, buf, &done, o)
 	if err != nil {
-		return false, newError("dbase-io-windows-deleted-2", err)
+		if errors.Is(err, windows.ERROR_HANDLE_EOF) {
+			return int(done), io.EOF
+		}
+		return int(done), err
 	}
-	_, err = windows.Seek(*handle, int64(file.header.FirstRow)+(int64(file.table.rowPointer)*int64(file.header.RowLength)), 0)
-	if err != nil {
-		return false, newError("dbase-io-windows-deleted-3", err)
+	if int(done) < len(buf) {
+		return int(done), io.EOF
+	}
+	return int(done), nil
+}
+
+// writeAt writes to the handle at the given offset without using the shared file pointer
+func (w WindowsIO) writeAt(handle *windows.Handle, buf []byte, offset int64) (int, error) {
+	o := &windows.Overlapped{
+		Offset:     uint32(offset),
+		OffsetHigh: uint32(offset >> 32),
 	}
-	buf := make([]byte, 1)
-	read, err := windows.Read(*handle, buf)
+	var done uint32
+	err := windows.WriteFile(*handle, buf, &done, o)
 	if err != nil {
-		return false, newError("dbase-io-windows-deleted-4", err)
+		return int(done), err
 	}
-	if read != 1 {
-		return false, newError("dbase-io-windows-deleted-5", ErrIncomplete)
+	if int(done) < len(buf) {
+		return int(done), io.ErrShortWrite
 	}
-	return Marker(buf[0]) == Deleted, nil
+	return int(done), nil
 }
 
 func _findFile(name string) (string, error) {
@@ -0,0 +1,225 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Size of the header and column descriptors of dBase level 7 tables
+const (
+	level7HeaderSize = 68
+	level7ColumnSize = 48
+)
+
+// Size of the database container backlink of Visual FoxPro tables, following the column terminator
+const backlinkSize = 263
+
+// Returns if the table is a dBase level 7 table (0x04 or 0x8C).
+// Level 7 tables have a larger header, column descriptors with 32 byte names and store binary numbers big endian.
+func (file *File) level7() bool {
+	return file.header != nil && file.header.Level7()
+}
+
+// Returns the offset of the first column descriptor
+func (file *File) columnsOffset() int64 {
+	if file.level7() {
+		return level7HeaderSize
+	}
+	return 32
+}
+
+// Returns the size of one column descriptor
+func (file *File) columnSize() int {
+	if file.level7() {
+		return level7ColumnSize
+	}
+	return 32
+}
+
+// Returns if the header contains the database container backlink (Visual FoxPro tables)
+func (file *File) hasBacklink() bool {
+	if file.header == nil {
+		return false
+	}
+	switch FileVersion(file.header.FileType) {
+	case FoxPro, FoxProAutoincrement, FoxProVar:
+		return true
+	}
+	return false
+}
+
+// Returns the position of the first row for the number of column descriptors, including the _NullFlags descriptor
+func (file *File) firstRow(descriptors int) uint16 {
+	size := file.columnsOffset() + int64(descriptors*file.columnSize()) + 1
+	if file.hasBacklink() {
+		size += backlinkSize
+	}
+	return uint16(size)
+}
+
+// Reads the column descriptors with the read function of the IO implementation until the terminator 0x0D.
+// The positions of the columns in the row are calculated from the column lengths as dBase III and IV tables do not store them.
+// The database container backlink following the terminator is kept to write it back with the columns.
+func (file *File) readColumns(readAt func(b []byte, offset int64) (int, error)) ([]*Column, *Column, error) {
+	var nullFlag *Column
+	columns := make([]*Column, 0)
+	offset := file.columnsOffset()
+	size := file.columnSize()
+	position := uint32(1)
+	for {
+		buf := make([]byte, size)
+		n, err := readAt(buf, offset)
+		if err != nil && !(errors.Is(err, io.EOF) && n > 0) {
+			return nil, nil, newError("dbase-layout-readcolumns-1", err)
+		}
+		// Check if we are at the column terminator 0x0D
+		if Marker(buf[0]) == ColumnEnd {
+			err = file.readBacklink(readAt, offset+1)
+			if err != nil {
+				return nil, nil, newError("dbase-layout-readcolumns-3", err)
+			}
+			break
+		}
+		column, err := file.decodeColumn(buf[:n])
+		if err != nil {
+			return nil, nil, newError("dbase-layout-readcolumns-2", err)
+		}
+		column.Position = position
+		position += uint32(column.Length)
+		offset += int64(size)
+		if column.Name() == "_NullFlags" {
+			debugf("Found null flag column: %s", column.Name())
+			nullFlag = column
+			continue
+		}
+		debugf("Found column %v of type %v at offset: %d", column.Name(), column.Type(), offset-int64(size))
+		columns = append(columns, column)
+	}
+	return columns, nullFlag, nil
+}
+
+// Reads the database container backlink at offset, the backlink is empty for free tables
+func (file *File) readBacklink(readAt func(b []byte, offset int64) (int, error), offset int64) error {
+	file.backlink = nil
+	if !file.hasBacklink() || offset >= int64(file.header.FirstRow) {
+		return nil
+	}
+	size := int64(file.header.FirstRow) - offset
+	if size > backlinkSize {
+		size = backlinkSize
+	}
+	buf := make([]byte, size)
+	n, err := readAt(buf, offset)
+	if err != nil && !(errors.Is(err, io.EOF) && n == len(buf)) {
+		return newError("dbase-layout-readbacklink-1", err)
+	}
+	file.backlink = buf[:n]
+	return nil
+}
+
+// Decodes a column descriptor, level 7 descriptors are kept to write them back unchanged
+func (file *File) decodeColumn(buf []byte) (*Column, error) {
+	column := &Column{}
+	if !file.level7() {
+		err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, column)
+		if err != nil {
+			return nil, newError("dbase-layout-decodecolumn-1", err)
+		}
+		return column, nil
+	}
+	if len(buf) != level7ColumnSize {
+		return nil, newError("dbase-layout-decodecolumn-2", fmt.Errorf("invalid column descriptor length %v", len(buf)))
+	}
+	name := buf[:32]
+	if i := bytes.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+	if len(name) > 10 {
+		debugf("Column name %s is shortened to 10 characters, the long name is returned by LongName", name)
+	}
+	copy(column.FieldName[:10], name)
+	column.DataType = buf[32]
+	column.Length = buf[33]
+	column.Decimals = buf[34]
+	if DataType(column.DataType) == Autoincrement {
+		column.Flag = byte(AutoincrementFlag)
+		column.Next = binary.LittleEndian.Uint32(buf[40:44])
+		column.Step = 1
+	}
+	if file.descriptors == nil {
+		file.descriptors = make(map[*Column][]byte)
+	}
+	file.descriptors[column] = append([]byte{}, buf...)
+	return column, nil
+}
+
+// LongName returns the name of the column with up to 32 characters as stored by dBase level 7 tables.
+// Column.Name is limited to 10 characters, for other tables both names are equal.
+func (file *File) LongName(column *Column) string {
+	raw, ok := file.descriptors[column]
+	if !ok {
+		return column.Name()
+	}
+	name := raw[:32]
+	if i := bytes.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+	return string(name)
+}
+
+// Encodes the column descriptors and the terminator as written after the header.
+// The database container backlink is written after the terminator and the remaining header space is filled with zeros,
+// level 7 tables keep the field properties after the terminator.
+func (file *File) encodeColumns() ([]byte, error) {
+	columns := file.table.columns
+	if file.nullFlagColumn != nil {
+		columns = append(append([]*Column{}, columns...), file.nullFlagColumn)
+	}
+	buf := new(bytes.Buffer)
+	for _, column := range columns {
+		debugf("Writing column: %+v", column)
+		if file.level7() {
+			buf.Write(file.encodeLevel7Column(column))
+			continue
+		}
+		err := binary.Write(buf, binary.LittleEndian, column)
+		if err != nil {
+			return nil, newError("dbase-layout-encodecolumns-1", err)
+		}
+	}
+	// Write the column terminator
+	buf.WriteByte(byte(ColumnEnd))
+	if file.level7() {
+		return buf.Bytes(), nil
+	}
+	if file.hasBacklink() {
+		buf.Write(file.backlink)
+	}
+	// Write null till the end of the header
+	end := int64(file.header.FirstRow) - file.columnsOffset()
+	if int64(buf.Len()) > end {
+		return nil, newError("dbase-layout-encodecolumns-2", fmt.Errorf("columns exceed the header size %v", file.header.FirstRow))
+	}
+	buf.Write(make([]byte, end-int64(buf.Len())))
+	return buf.Bytes(), nil
+}
+
+// Encodes a level 7 column descriptor, the long name and reserved bytes of read columns are kept
+func (file *File) encodeLevel7Column(column *Column) []byte {
+	buf := make([]byte, level7ColumnSize)
+	if raw, ok := file.descriptors[column]; ok {
+		copy(buf, raw)
+	} else {
+		copy(buf[:32], column.Name())
+	}
+	buf[32] = column.DataType
+	buf[33] = column.Length
+	buf[34] = column.Decimals
+	if DataType(column.DataType) == Autoincrement {
+		binary.LittleEndian.PutUint32(buf[40:44], column.Next)
+	}
+	return buf
+}
@@ -0,0 +1,296 @@
+package dbase
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cached struct fields by column name per struct type
+var mappers sync.Map
+
+// Returns the struct fields of the type by column name, the result is cached per type
+func mapperOf(t reflect.Type) (map[string]structField, error) {
+	if cached, ok := mappers.Load(t); ok {
+		return cached.(map[string]structField), nil
+	}
+	fields, err := structFields(t)
+	if err != nil {
+		return nil, newError("dbase-mapper-mapperof-1", err)
+	}
+	mapper := make(map[string]structField, len(fields))
+	for _, f := range fields {
+		mapper[f.tag.name] = f
+	}
+	mappers.Store(t, mapper)
+	return mapper, nil
+}
+
+// Scan maps the row to a new value of the struct type T (or pointer to struct).
+// Fields are matched by the dbase tag or the upper case field name (external keys of modifications take precedence),
+// fields of embedded structs are mapped as well. Values are assigned without a map round trip:
+//   - Pointer fields are set to nil for null values
+//   - Numeric values are converted between Go number types if the value fits without loss
+//   - Fields implementing sql.Scanner (e.g. sql.NullString) receive the column value
+//
+// An error describing the column, value and field is returned if a value can not be assigned.
+func Scan[T any](row *Row) (T, error) {
+	var result T
+	rv := reflect.ValueOf(&result).Elem()
+	if rv.Kind() == reflect.Ptr {
+		rv.Set(reflect.New(rv.Type().Elem()))
+		rv = rv.Elem()
+	}
+	err := row.scan(rv)
+	if err != nil {
+		return result, newError("dbase-mapper-scan-1", err)
+	}
+	return result, nil
+}
+
+// All maps all not deleted rows of the table to values of the struct type T (or pointer to struct)
+func All[T any](file *File) ([]T, error) {
+	result := make([]T, 0, file.RowsCount())
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return nil, newError("dbase-mapper-all-1", err)
+		}
+		if row.Deleted {
+			continue
+		}
+		v, err := Scan[T](row)
+		if err != nil {
+			return nil, newError("dbase-mapper-all-2", err)
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// Maps the row fields to the struct value
+func (row *Row) scan(rv reflect.Value) error {
+	if rv.Kind() != reflect.Struct {
+		return newError("dbase-mapper-scan-6", fmt.Errorf("expected struct, got %v", rv.Kind()))
+	}
+	mapper, err := mapperOf(rv.Type())
+	if err != nil {
+		return newError("dbase-mapper-scan-2", err)
+	}
+	for i, field := range row.fields {
+		name := field.Name()
+		var mod *Modification
+		if i < len(row.handle.table.mods) {
+			mod = row.handle.table.mods[i]
+		}
+		if mod != nil && len(mod.ExternalKey) != 0 {
+			name = mod.ExternalKey
+		}
+		f, ok := mapper[name]
+		if !ok {
+			continue
+		}
+		value, err := field.Value()
+		if err != nil {
+			return newError("dbase-mapper-scan-3", fmt.Errorf("interpreting column %v failed with error: %w", field.Name(), err))
+		}
+		if row.isNull(field, value) {
+			value = nil
+		}
+		if s, ok := value.(string); ok && (row.handle.config.TrimSpaces || (mod != nil && mod.TrimSpaces)) {
+			value = strings.TrimSpace(s)
+		}
+		if mod != nil && mod.Convert != nil {
+			value, err = mod.Convert(value)
+			if err != nil {
+				return newError("dbase-mapper-scan-4", err)
+			}
+		}
+		target, ok := fieldByIndex(rv, f.index, value != nil)
+		if !ok {
+			continue
+		}
+		err = assign(target, value)
+		if err != nil {
+			return newError("dbase-mapper-scan-5", fmt.Errorf("column %v (%v) to field %v: %w", field.Name(), field.Type(), f.field.Name, err))
+		}
+	}
+	return nil
+}
+
+// Returns if the value read from a varchar or varbinary column is null according to the _NullFlags field
+func (row *Row) isNull(field *Field, value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if b, ok := value.([]byte); !ok || len(b) > 0 || row.nullFlags == nil {
+		return false
+	}
+	if field.column.DataType != byte(Varchar) && field.column.DataType != byte(Varbinary) {
+		return false
+	}
+	_, null, err := row.handle.readNullFlag(row.nullFlags, field.column)
+	return err == nil && null
+}
+
+// Assigns the column value to the struct field, lossy conversions return an error
+func assign(dst reflect.Value, value interface{}) error {
+	if dst.CanAddr() {
+		if scanner, ok := dst.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(value)
+		}
+	}
+	if dst.Kind() == reflect.Ptr {
+		if value == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		ptr := reflect.New(dst.Type().Elem())
+		err := assign(ptr.Elem(), value)
+		if err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	}
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	src := reflect.ValueOf(value)
+	if src.Type() == dst.Type() {
+		dst.Set(src)
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Interface:
+		if src.Type().Implements(dst.Type()) {
+			dst.Set(src)
+			return nil
+		}
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			dst.SetString(v)
+			return nil
+		case []byte:
+			dst.SetString(string(v))
+			return nil
+		}
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			switch v := value.(type) {
+			case []byte:
+				dst.SetBytes(v)
+				return nil
+			case string:
+				dst.SetBytes([]byte(v))
+				return nil
+			}
+		}
+	case reflect.Bool:
+		if v, ok := value.(bool); ok {
+			dst.SetBool(v)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok, err := integerOf(value)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if dst.OverflowInt(i) {
+				return fmt.Errorf("value %v overflows %v", value, dst.Type())
+			}
+			dst.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok, err := integerOf(value)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if i < 0 || dst.OverflowUint(uint64(i)) {
+				return fmt.Errorf("value %v overflows %v", value, dst.Type())
+			}
+			dst.SetUint(uint64(i))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		ok := true
+		switch v := value.(type) {
+		case float64:
+			f = v
+		case Decimal:
+			f = v.Float64()
+		case int32:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		default:
+			ok = false
+		}
+		if ok {
+			if dst.OverflowFloat(f) {
+				return fmt.Errorf("value %v overflows %v", value, dst.Type())
+			}
+			dst.SetFloat(f)
+			return nil
+		}
+	case reflect.Struct:
+		if t, ok := value.(time.Time); ok && dst.Type().ConvertibleTo(reflect.TypeOf(t)) {
+			dst.Set(reflect.ValueOf(t).Convert(dst.Type()))
+			return nil
+		}
+		if dst.Type() == reflect.TypeOf(Decimal{}) {
+			var d Decimal
+			var err error
+			switch v := value.(type) {
+			case float64:
+				d, err = ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+			case int64:
+				d = NewDecimal(v, 0)
+			case int32:
+				d = NewDecimal(int64(v), 0)
+			}
+			if err != nil {
+				return err
+			}
+			if d.unscaled != nil {
+				dst.Set(reflect.ValueOf(d))
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("can not assign value of type %T to %v", value, dst.Type())
+}
+
+// Returns the value as int64 if it is an integer number, floats with a fraction return an error
+func integerOf(value interface{}) (int64, bool, error) {
+	switch v := value.(type) {
+	case int32:
+		return int64(v), true, nil
+	case int64:
+		return v, true, nil
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false, fmt.Errorf("value %v is not an integer", v)
+		}
+		return int64(v), true, nil
+	case Decimal:
+		i, ok := v.Int64()
+		if !ok {
+			return 0, false, fmt.Errorf("value %v is not an integer", v)
+		}
+		return i, true, nil
+	}
+	return 0, false, nil
+}
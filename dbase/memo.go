@@ -0,0 +1,425 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/transform"
+)
+
+// Size of the chunks converted by converters without stream support
+const memoChunkSize = 4096
+
+// MemoReader returns a reader streaming the memo of the column (M, G, P or W) of the row from the memo file.
+// Text memos are decoded with the converter while reading. If the row has been written the memo address is read from the table.
+// If the IO implementation does not implement MemoIO the memo is read at once.
+func (file *File) MemoReader(row *Row, column string) (io.ReadCloser, error) {
+	field, err := file.memoField(row, column)
+	if err != nil {
+		return nil, newError("dbase-memo-memoreader-1", err)
+	}
+	address, err := file.memoAddress(row, field)
+	if err != nil {
+		return nil, newError("dbase-memo-memoreader-2", err)
+	}
+	block := binary.LittleEndian.Uint32(address)
+	if block == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	memoIO, ok := file.defaults().io.(MemoIO)
+	if !ok {
+		data, _, err := file.ReadMemo(address)
+		if err != nil {
+			return nil, newError("dbase-memo-memoreader-3", err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	position := int64(block) * int64(file.memoHeader.BlockSize)
+	header := make([]byte, 8)
+	_, err = memoIO.ReadMemoAt(file, header, position)
+	if err != nil {
+		return nil, newError("dbase-memo-memoreader-4", err)
+	}
+	sign := binary.BigEndian.Uint32(header[:4])
+	length := binary.BigEndian.Uint32(header[4:])
+	debugf("Streaming memo block %d at position %d => text: %v, length: %d", block, position, sign == 1, length)
+	var r io.Reader = io.NewSectionReader(memoReaderAt{file: file, io: memoIO}, position+8, int64(length))
+	if sign == 1 {
+		if c, ok := file.config.Converter.(StreamConverter); ok {
+			r = transform.NewReader(r, c.Decoder())
+		} else {
+			r = &chunkReader{source: r, convert: file.config.Converter.Decode}
+		}
+	}
+	return io.NopCloser(r), nil
+}
+
+// MemoWriter returns a writer streaming a new memo of the column (M, G, P or W) of the row to the memo file.
+// Text of M columns (without binary flag) is encoded with the converter while writing.
+// Closing the writer completes the memo block header and updates the memo address of the row,
+// rows that have been written are updated in the table. The blocks of the memo are reserved while writing,
+// so other memos can be written before the writer is closed.
+// If the IO implementation does not implement MemoIO the memo is buffered and written on close.
+func (file *File) MemoWriter(row *Row, column string) (io.WriteCloser, error) {
+	if file.config.ReadOnly {
+		return nil, newError("dbase-memo-memowriter-1", errors.New("table is opened read-only"))
+	}
+	field, err := file.memoField(row, column)
+	if err != nil {
+		return nil, newError("dbase-memo-memowriter-2", err)
+	}
+	if file.memoHeader == nil {
+		return nil, newError("dbase-memo-memowriter-3", ErrNoFPT)
+	}
+	w := file.newMemoWriter(DataType(field.column.DataType) == Memo && field.column.Flag&byte(BinaryFlag) == 0)
+	w.row = row
+	w.field = field
+	return w, nil
+}
+
+// Returns a writer appending a new memo to the memo file, finish returns the memo address
+func (file *File) newMemoWriter(text bool) *memoWriter {
+	w := &memoWriter{
+		file: file,
+		text: text,
+	}
+	if memoIO, ok := file.defaults().io.(MemoIO); ok {
+		// The memo is appended at the next free block, the blocks are reserved by the writes
+		file.memoMutex.Lock()
+		w.io = memoIO
+		w.block = file.memoHeader.NextFree
+		w.end = w.block
+		w.offset = int64(w.block)*int64(file.memoHeader.BlockSize) + 8
+		file.memoMutex.Unlock()
+		debugf("Streaming memo to block %d", w.block)
+	} else {
+		w.buf = new(bytes.Buffer)
+	}
+	w.target = rawMemoWriter{w}
+	if w.text {
+		if c, ok := file.config.Converter.(StreamConverter); ok {
+			encoder := c.Encoder()
+			w.handler, _ = encoder.(*unencodableHandler)
+			w.target = transform.NewWriter(rawMemoWriter{w}, encoder)
+		} else if c, ok := file.config.Converter.(LossyConverter); ok {
+			w.target = &chunkWriter{target: rawMemoWriter{w}, convert: func(in []byte) ([]byte, error) {
+				data, lossy, err := c.EncodeLossy(in)
+				w.lossy = w.lossy || lossy
+				return data, err
+			}}
+		} else {
+			w.target = &chunkWriter{target: rawMemoWriter{w}, convert: file.config.Converter.Encode}
+		}
+	}
+	return w
+}
+
+// Returns the memo field of the row by column name
+func (file *File) memoField(row *Row, column string) (*Field, error) {
+	if row == nil || row.handle != file {
+		return nil, newError("dbase-memo-memofield-1", errors.New("row does not belong to the table"))
+	}
+	field := row.FieldByName(column)
+	if field == nil {
+		return nil, newError("dbase-memo-memofield-2", fmt.Errorf("column %v not found", column))
+	}
+	if !DataType(field.column.DataType).memo() {
+		return nil, newError("dbase-memo-memofield-3", fmt.Errorf("column %v of type %v is not stored in the memo file", field.Name(), field.Type()))
+	}
+	return field, nil
+}
+
+// Returns the memo address of the field, read from the table if the row has been written
+func (file *File) memoAddress(row *Row, field *Field) ([]byte, error) {
+	if row.Position < file.header.RowsCount {
+		data, err := file.ReadRow(row.Position)
+		if err != nil {
+			return nil, newError("dbase-memo-memoaddress-1", err)
+		}
+		return data[field.column.Position : field.column.Position+uint32(field.column.Length)], nil
+	}
+	if field.raw != nil {
+		return field.raw, nil
+	}
+	if field.value == nil {
+		return make([]byte, 4), nil
+	}
+	return nil, newError("dbase-memo-memoaddress-2", fmt.Errorf("memo of column %v has not been written", field.Name()))
+}
+
+// Reads the memo at the address without decoding text, up to limit bytes if limit is greater than 0.
+// Returns if the memo is text. If the IO implementation does not implement MemoIO text is read with ReadMemo and encoded again.
+func (file *File) readRawMemo(address []byte, limit int) ([]byte, bool, error) {
+	if file.memoHeader == nil || len(address) != 4 || binary.LittleEndian.Uint32(address) == 0 {
+		return nil, false, nil
+	}
+	memoIO, ok := file.defaults().io.(MemoIO)
+	if !ok {
+		data, text, err := file.ReadMemo(address)
+		if err != nil {
+			return nil, false, newError("dbase-memo-readrawmemo-1", err)
+		}
+		if text {
+			data, err = file.config.Converter.Encode(data)
+			if err != nil {
+				return nil, false, newError("dbase-memo-readrawmemo-2", err)
+			}
+		}
+		if limit > 0 && len(data) > limit {
+			data = data[:limit]
+		}
+		return data, text, nil
+	}
+	position := int64(binary.LittleEndian.Uint32(address)) * int64(file.memoHeader.BlockSize)
+	header := make([]byte, 8)
+	_, err := memoIO.ReadMemoAt(file, header, position)
+	if err != nil {
+		return nil, false, newError("dbase-memo-readrawmemo-3", err)
+	}
+	text := binary.BigEndian.Uint32(header[:4]) == 1
+	length := int(binary.BigEndian.Uint32(header[4:]))
+	if limit > 0 && length > limit {
+		length = limit
+	}
+	data := make([]byte, length)
+	n, err := memoIO.ReadMemoAt(file, data, position+8)
+	if err != nil && n < len(data) {
+		return nil, false, newError("dbase-memo-readrawmemo-4", err)
+	}
+	return data, text, nil
+}
+
+// Adapts the positional memo access of the IO implementation to io.ReaderAt
+type memoReaderAt struct {
+	file *File
+	io   MemoIO
+}
+
+func (r memoReaderAt) ReadAt(p []byte, offset int64) (int, error) {
+	return r.io.ReadMemoAt(r.file, p, offset)
+}
+
+// Streams a new memo to the memo file
+type memoWriter struct {
+	file   *File
+	row    *Row   // Row updated on close, nil if the address is used by the caller
+	field  *Field // Field updated on close
+	text   bool
+	io     MemoIO        // Positional access to the memo file, nil if buffered
+	buf    *bytes.Buffer // Buffered data if the IO implementation does not implement MemoIO
+	block  uint32        // First block of the memo
+	end    uint32        // First block after the reserved blocks
+	offset int64         // Position of the next write
+	length int           // Number of bytes written
+	target io.Writer     // Writer encoding text or rawMemoWriter
+	closed bool
+	// Characters were replaced when the text was encoded, see Field.Lossy
+	lossy   bool
+	handler *unencodableHandler // Encoder of stream converters counting the replaced characters
+
+}
+
+// Write writes the data to the memo
+func (w *memoWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, newError("dbase-memo-write-1", errors.New("memo writer is closed"))
+	}
+	return w.target.Write(p)
+}
+
+// Close completes the memo block header and updates the memo address of the row
+func (w *memoWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	address, err := w.finish()
+	if err != nil {
+		return newError("dbase-memo-close-1", err)
+	}
+	w.field.value = nil
+	w.field.raw = address
+	w.field.row = w.row
+	w.field.lossy = w.lossy
+	if w.row.Position >= w.file.header.RowsCount {
+		// The address is written with the row
+		return nil
+	}
+	// Only the memo address is changed, the other fields are written back unchanged
+	data, err := w.file.ReadRow(w.row.Position)
+	if err != nil {
+		return newError("dbase-memo-close-2", err)
+	}
+	stored, err := w.file.bytesToRow(data, w.row.Position, map[int]bool{})
+	if err != nil {
+		return newError("dbase-memo-close-3", err)
+	}
+	for _, f := range stored.fields {
+		if f.column == w.field.column {
+			f.raw = address
+		}
+	}
+	err = w.file.WriteRow(stored)
+	if err != nil {
+		return newError("dbase-memo-close-4", err)
+	}
+	return nil
+}
+
+// Completes the memo block header and returns the memo address
+func (w *memoWriter) finish() ([]byte, error) {
+	w.closed = true
+	if closer, ok := w.target.(io.Closer); ok {
+		err := closer.Close()
+		if err != nil {
+			return nil, newError("dbase-memo-finish-1", err)
+		}
+	}
+	if w.handler != nil && w.handler.replaced > 0 {
+		w.lossy = true
+	}
+	if w.io == nil {
+		address, err := w.file.WriteMemo(w.buf.Bytes(), w.text, w.buf.Len())
+		if err != nil {
+			return nil, newError("dbase-memo-finish-2", err)
+		}
+		return address, nil
+	}
+	// Reserve the block of the header for empty memos
+	err := w.reserve(w.length)
+	if err != nil {
+		return nil, newError("dbase-memo-finish-5", err)
+	}
+	w.file.memoMutex.Lock()
+	defer w.file.memoMutex.Unlock()
+	header := make([]byte, 8)
+	if w.text {
+		binary.BigEndian.PutUint32(header[:4], 1)
+	}
+	binary.BigEndian.PutUint32(header[4:], uint32(w.length))
+	_, err = w.io.WriteMemoAt(w.file, header, int64(w.block)*int64(w.file.memoHeader.BlockSize))
+	if err != nil {
+		return nil, newError("dbase-memo-finish-3", err)
+	}
+	// The reserved blocks are already included in the next free block
+	err = w.file.WriteMemoHeader(0)
+	if err != nil {
+		return nil, newError("dbase-memo-finish-4", err)
+	}
+	debugf("Streamed memo to block %d with %d bytes", w.block, w.length)
+	address := make([]byte, 4)
+	binary.LittleEndian.PutUint32(address, w.block)
+	return address, nil
+}
+
+// Reserves the blocks for a memo of length bytes at the end of the memo file.
+// If other memos have been written after the reserved blocks, the written data is moved to the next free block.
+func (w *memoWriter) reserve(length int) error {
+	file := w.file
+	file.memoMutex.Lock()
+	defer file.memoMutex.Unlock()
+	size := int64(file.memoHeader.BlockSize)
+	blocks := uint32((int64(length) + 8 + size - 1) / size)
+	if w.block+blocks <= w.end {
+		return nil
+	}
+	if file.memoHeader.NextFree != w.end {
+		next := file.memoHeader.NextFree
+		debugf("Moving streamed memo from block %d to block %d", w.block, next)
+		buf := make([]byte, memoChunkSize)
+		for copied := 0; copied < w.length; {
+			chunk := buf
+			if w.length-copied < len(chunk) {
+				chunk = chunk[:w.length-copied]
+			}
+			_, err := w.io.ReadMemoAt(file, chunk, int64(w.block)*size+8+int64(copied))
+			if err != nil {
+				return newError("dbase-memo-reserve-1", err)
+			}
+			_, err = w.io.WriteMemoAt(file, chunk, int64(next)*size+8+int64(copied))
+			if err != nil {
+				return newError("dbase-memo-reserve-2", err)
+			}
+			copied += len(chunk)
+		}
+		w.block = next
+		w.end = next
+		w.offset = int64(next)*size + 8 + int64(w.length)
+	}
+	file.memoHeader.NextFree += w.block + blocks - w.end
+	w.end = w.block + blocks
+	return nil
+}
+
+// Writes the (encoded) data to the memo file or the buffer
+type rawMemoWriter struct {
+	w *memoWriter
+}
+
+func (r rawMemoWriter) Write(p []byte) (int, error) {
+	w := r.w
+	if w.io == nil {
+		w.length += len(p)
+		return w.buf.Write(p)
+	}
+	err := w.reserve(w.length + len(p))
+	if err != nil {
+		return 0, newError("dbase-memo-write-4", err)
+	}
+	n, err := w.io.WriteMemoAt(w.file, p, w.offset)
+	w.offset += int64(n)
+	w.length += n
+	if err != nil {
+		return n, newError("dbase-memo-write-2", err)
+	}
+	return n, nil
+}
+
+// Converts the data read from the source in chunks
+type chunkReader struct {
+	source  io.Reader
+	convert func([]byte) ([]byte, error)
+	pending []byte
+	err     error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		chunk := make([]byte, memoChunkSize)
+		n, err := r.source.Read(chunk)
+		r.err = err
+		if n > 0 {
+			converted, err := r.convert(chunk[:n])
+			if err != nil {
+				return 0, newError("dbase-memo-read-1", err)
+			}
+			r.pending = converted
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// Converts each write before writing it to the target
+type chunkWriter struct {
+	target  io.Writer
+	convert func([]byte) ([]byte, error)
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	converted, err := w.convert(p)
+	if err != nil {
+		return 0, newError("dbase-memo-write-3", err)
+	}
+	_, err = w.target.Write(converted)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,106 @@
+package dbase
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ExportNDJSON writes every not deleted row as JSON object on its own line (newline-delimited JSON) and returns the number of written rows.
+// The rows are read one by one and converted like ToJSON: modifications (external keys, TrimSpaces and conversions) are applied and
+// binary values (Varbinary, Blob, General and Picture) are base64 encoded.
+func (file *File) ExportNDJSON(w io.Writer) (int, error) {
+	debugf("Exporting table %v as NDJSON...", file.config.Filename)
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	count := 0
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return count, newError("dbase-ndjson-exportndjson-1", err)
+		}
+		if row.Deleted {
+			continue
+		}
+		m, err := row.ToMap()
+		if err != nil {
+			return count, newError("dbase-ndjson-exportndjson-2", err)
+		}
+		err = encoder.Encode(m)
+		if err != nil {
+			return count, newError("dbase-ndjson-exportndjson-3", fmt.Errorf("encoding row %v failed with error: %w", row.Position, err))
+		}
+		count++
+	}
+	err := buffered.Flush()
+	if err != nil {
+		return count, newError("dbase-ndjson-exportndjson-4", err)
+	}
+	return count, nil
+}
+
+// ImportNDJSON appends every JSON object of the newline-delimited JSON and returns the number of appended rows.
+// The objects are decoded one by one and converted like RowFromMap, keys are column names or external keys of the modifications.
+// Binary values (Varbinary, Blob, General, Picture and binary memos) are expected base64 encoded. Autoincrement columns get new values.
+func (file *File) ImportNDJSON(r io.Reader) (int, error) {
+	if file.config.ReadOnly {
+		return 0, newError("dbase-ndjson-importndjson-1", errors.New("table is opened read-only"))
+	}
+	debugf("Importing NDJSON into table %v...", file.config.Filename)
+	binary := make(map[string]bool)
+	for i, column := range file.table.columns {
+		if !binaryColumn(column) {
+			continue
+		}
+		binary[column.Name()] = true
+		if mod := file.table.mods[i]; mod != nil && len(mod.ExternalKey) != 0 {
+			binary[mod.ExternalKey] = true
+		}
+	}
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	decoder.UseNumber()
+	count := 0
+	for {
+		m := make(map[string]interface{})
+		err := decoder.Decode(&m)
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, newError("dbase-ndjson-importndjson-2", fmt.Errorf("decoding object %v failed with error: %w", count+1, err))
+		}
+		for key, value := range m {
+			if s, ok := value.(string); ok && binary[key] {
+				data, err := base64.StdEncoding.DecodeString(s)
+				if err != nil {
+					return count, newError("dbase-ndjson-importndjson-3", fmt.Errorf("decoding %v of object %v failed with error: %w", key, count+1, err))
+				}
+				m[key] = data
+			}
+		}
+		row, err := file.RowFromMap(m)
+		if err != nil {
+			return count, newError("dbase-ndjson-importndjson-4", fmt.Errorf("object %v: %w", count+1, err))
+		}
+		err = row.Add()
+		if err != nil {
+			return count, newError("dbase-ndjson-importndjson-5", fmt.Errorf("object %v: %w", count+1, err))
+		}
+		count++
+	}
+}
+
+// Returns if the values of the column are binary data encoded as base64 in JSON
+func binaryColumn(column *Column) bool {
+	switch DataType(column.DataType) {
+	case Varbinary, Blob, General, Picture:
+		return true
+	case Memo:
+		return column.Flag&byte(BinaryFlag) != 0
+	}
+	return false
+}
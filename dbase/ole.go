@@ -0,0 +1,520 @@
+package dbase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// Signature of OLE2 compound files
+var compoundSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// Special sector numbers of compound files
+const (
+	endOfChain = 0xFFFFFFFE
+	freeSector = 0xFFFFFFFF
+)
+
+// Format and version of OLE 1.0 embedded objects
+const (
+	oleVersion  = 0x00000501
+	oleEmbedded = 0x00000002
+)
+
+// MIME types of OLE objects by class name prefix, more specific prefixes first
+var oleClassTypes = [][2]string{
+	{"Word.Document.12", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+	{"Excel.Sheet.12", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	{"PowerPoint.Show.12", "application/vnd.openxmlformats-officedocument.presentationml.presentation"},
+	{"Word.Document", "application/msword"},
+	{"Excel.", "application/vnd.ms-excel"},
+	{"PowerPoint.", "application/vnd.ms-powerpoint"},
+	{"AcroExch.Document", "application/pdf"},
+	{"Paint.Picture", "image/bmp"},
+	{"PBrush", "image/bmp"},
+}
+
+// OLEObject is an object embedded in a General (G) or Picture (P) column.
+// The memo data is an OLE 1.0 embedded object, the native data of OLE2 objects is a compound file.
+type OLEObject struct {
+	Class string // OLE class name (e.g. "Paint.Picture", "Word.Document.8", "Package")
+	Name  string // File name of Package objects
+	MIME  string // Detected MIME type of the data
+	Data  []byte // The embedded object (e.g. the BMP image, the Word document or the packaged file)
+	Raw   []byte // The raw memo data, written unchanged if set
+}
+
+// ParseOLEObject parses the memo data of a General or Picture column.
+// The OLE 1.0 header and the OLE2 compound file are unwrapped, the embedded object is returned as Data.
+// Data that is not wrapped is returned as it is. Empty data returns nil.
+func ParseOLEObject(data []byte) (*OLEObject, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	obj := &OLEObject{Raw: data, Data: data}
+	native := data
+	if class, nativeData, ok := parseOLEHeader(data); ok {
+		obj.Class = class
+		native = nativeData
+	}
+	if bytes.HasPrefix(native, compoundSignature) {
+		err := obj.parseCompound(native)
+		if err != nil {
+			return nil, newError("dbase-ole-parseoleobject-1", err)
+		}
+	} else if obj.Class == "Package" {
+		obj.parsePackage(native)
+	} else {
+		obj.Data = native
+	}
+	obj.MIME = obj.detectMIME()
+	return obj, nil
+}
+
+// OLEObject returns the object of a General or Picture field
+func (field *Field) OLEObject() (*OLEObject, error) {
+	switch DataType(field.column.DataType) {
+	case General, Picture:
+	default:
+		return nil, newError("dbase-ole-oleobject-1", fmt.Errorf("column %v of type %v does not contain OLE objects", field.Name(), field.Type()))
+	}
+	value, err := field.Value()
+	if err != nil {
+		return nil, newError("dbase-ole-oleobject-4", err)
+	}
+	data, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			return nil, nil
+		}
+		return nil, newError("dbase-ole-oleobject-2", fmt.Errorf("invalid data type %T, expected []byte at column field: %v", value, field.Name()))
+	}
+	obj, err := ParseOLEObject(data)
+	if err != nil {
+		return nil, newError("dbase-ole-oleobject-3", err)
+	}
+	return obj, nil
+}
+
+// Bytes returns the memo data of the object.
+// Raw is returned if set, otherwise the data is wrapped as OLE 1.0 embedded object of the class (Package if empty).
+func (obj OLEObject) Bytes() []byte {
+	if obj.Raw != nil {
+		return obj.Raw
+	}
+	class := obj.Class
+	if len(class) == 0 {
+		class = "Package"
+	}
+	native := obj.Data
+	if class == "Package" {
+		native = packageData(obj.Name, obj.Data)
+	}
+	buf := new(bytes.Buffer)
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header[:4], oleVersion)
+	binary.LittleEndian.PutUint32(header[4:], oleEmbedded)
+	buf.Write(header)
+	writeOLEString(buf, class)
+	writeOLEString(buf, "")
+	writeOLEString(buf, "")
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(len(native)))
+	buf.Write(size)
+	buf.Write(native)
+	return buf.Bytes()
+}
+
+// Returns the class name and native data of an OLE 1.0 embedded object
+func parseOLEHeader(data []byte) (string, []byte, bool) {
+	if len(data) < 8 || binary.LittleEndian.Uint32(data[:4]) != oleVersion || binary.LittleEndian.Uint32(data[4:8]) != oleEmbedded {
+		return "", nil, false
+	}
+	rest := data[8:]
+	class, rest, ok := readOLEString(rest)
+	if !ok {
+		return "", nil, false
+	}
+	// Topic and item name are empty for embedded objects
+	for i := 0; i < 2; i++ {
+		_, rest, ok = readOLEString(rest)
+		if !ok {
+			return "", nil, false
+		}
+	}
+	if len(rest) < 4 {
+		return "", nil, false
+	}
+	size := binary.LittleEndian.Uint32(rest[:4])
+	rest = rest[4:]
+	if uint64(size) > uint64(len(rest)) {
+		return "", nil, false
+	}
+	return class, rest[:size], true
+}
+
+// Reads a length prefixed and null terminated string
+func readOLEString(data []byte) (string, []byte, bool) {
+	if len(data) < 4 {
+		return "", nil, false
+	}
+	length := binary.LittleEndian.Uint32(data[:4])
+	data = data[4:]
+	if uint64(length) > uint64(len(data)) {
+		return "", nil, false
+	}
+	return string(bytes.TrimRight(data[:length], "\x00")), data[length:], true
+}
+
+// Writes a length prefixed and null terminated string, empty strings have a length of 0
+func writeOLEString(buf *bytes.Buffer, s string) {
+	length := make([]byte, 4)
+	if len(s) == 0 {
+		buf.Write(length)
+		return
+	}
+	binary.LittleEndian.PutUint32(length, uint32(len(s)+1))
+	buf.Write(length)
+	buf.WriteString(s)
+	buf.WriteByte(0)
+}
+
+// Extracts the object from the compound file.
+// OLE 1.0 objects are stored in the \x01Ole10Native stream, other objects (e.g. Word documents) are the compound file itself.
+func (obj *OLEObject) parseCompound(data []byte) error {
+	cf, err := parseCompoundFile(data)
+	if err != nil {
+		return newError("dbase-ole-parsecompound-1", err)
+	}
+	if compObj, ok, err := cf.stream("\x01CompObj"); err == nil && ok {
+		if class := parseCompObj(compObj); len(class) > 0 {
+			obj.Class = class
+		}
+	}
+	native, ok, err := cf.stream("\x01Ole10Native")
+	if err != nil {
+		return newError("dbase-ole-parsecompound-2", err)
+	}
+	if ok && len(native) >= 4 {
+		size := binary.LittleEndian.Uint32(native[:4])
+		native = native[4:]
+		if uint64(size) <= uint64(len(native)) {
+			native = native[:size]
+		}
+		if obj.Class == "Package" {
+			obj.parsePackage(native)
+			return nil
+		}
+		obj.Data = native
+		return nil
+	}
+	for _, name := range []string{"Package", "CONTENTS", "Contents"} {
+		content, ok, err := cf.stream(name)
+		if err != nil {
+			return newError("dbase-ole-parsecompound-3", err)
+		}
+		if ok {
+			obj.Data = content
+			return nil
+		}
+	}
+	obj.Data = data
+	return nil
+}
+
+// Returns the ProgID of the \x01CompObj stream
+func parseCompObj(data []byte) string {
+	if len(data) < 28 {
+		return ""
+	}
+	rest := data[28:]
+	// User type
+	_, rest, ok := readOLEString(rest)
+	if !ok || len(rest) < 4 {
+		return ""
+	}
+	// Clipboard format as string or as standard format id
+	if marker := binary.LittleEndian.Uint32(rest[:4]); marker == 0xFFFFFFFF || marker == 0xFFFFFFFE {
+		if len(rest) < 8 {
+			return ""
+		}
+		rest = rest[8:]
+	} else {
+		_, rest, ok = readOLEString(rest)
+		if !ok {
+			return ""
+		}
+	}
+	progID, _, ok := readOLEString(rest)
+	if !ok {
+		return ""
+	}
+	return progID
+}
+
+// Extracts the file name and content of a packaged file, other package types are kept as they are
+func (obj *OLEObject) parsePackage(data []byte) {
+	obj.Data = data
+	if len(data) < 2 {
+		return
+	}
+	rest := data[2:]
+	label, rest, ok := cutString(rest)
+	if !ok {
+		return
+	}
+	_, rest, ok = cutString(rest)
+	if !ok || len(rest) < 8 || binary.LittleEndian.Uint16(rest[2:4]) != 3 {
+		return
+	}
+	rest = rest[4:]
+	pathLength := binary.LittleEndian.Uint32(rest[:4])
+	rest = rest[4:]
+	if uint64(pathLength)+4 > uint64(len(rest)) {
+		return
+	}
+	rest = rest[pathLength:]
+	size := binary.LittleEndian.Uint32(rest[:4])
+	rest = rest[4:]
+	if uint64(size) > uint64(len(rest)) {
+		return
+	}
+	obj.Name = label
+	obj.Data = rest[:size]
+}
+
+// Returns the native data of a Package object containing the file
+func packageData(name string, data []byte) []byte {
+	buf := new(bytes.Buffer)
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint16(b[:2], 2)
+	buf.Write(b[:2])
+	buf.WriteString(name)
+	buf.WriteByte(0)
+	buf.WriteString(name)
+	buf.WriteByte(0)
+	binary.LittleEndian.PutUint32(b, 0x00030000)
+	buf.Write(b)
+	binary.LittleEndian.PutUint32(b, uint32(len(name)+1))
+	buf.Write(b)
+	buf.WriteString(name)
+	buf.WriteByte(0)
+	binary.LittleEndian.PutUint32(b, uint32(len(data)))
+	buf.Write(b)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// Returns the null terminated string and the remaining data
+func cutString(data []byte) (string, []byte, bool) {
+	i := bytes.IndexByte(data, 0)
+	if i < 0 {
+		return "", nil, false
+	}
+	return string(data[:i]), data[i+1:], true
+}
+
+// Returns the MIME type of the object by file name, content or class name
+func (obj *OLEObject) detectMIME() string {
+	if len(obj.Name) > 0 {
+		if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(obj.Name))); len(t) > 0 {
+			return t
+		}
+	}
+	classType := ""
+	for _, c := range oleClassTypes {
+		if strings.HasPrefix(obj.Class, c[0]) {
+			classType = c[1]
+			break
+		}
+	}
+	if bytes.HasPrefix(obj.Data, compoundSignature) {
+		// Documents stored as compound file (e.g. Word 97-2003)
+		if strings.HasPrefix(classType, "application/") {
+			return classType
+		}
+		return "application/x-ole-storage"
+	}
+	t := http.DetectContentType(obj.Data)
+	if len(classType) > 0 && (t == "application/octet-stream" || t == "application/zip") {
+		return classType
+	}
+	return t
+}
+
+// A compound file directory entry
+type compoundEntry struct {
+	name  string
+	kind  byte
+	start uint32
+	size  uint64
+}
+
+// A parsed OLE2 compound file (structured storage)
+type compoundFile struct {
+	data           []byte
+	sectorSize     int
+	miniSectorSize int
+	cutoff         uint64
+	fat            []uint32
+	miniFAT        []uint32
+	miniStream     []byte
+	entries        []compoundEntry
+}
+
+// Parses the header, sector allocation tables and directory of the compound file
+func parseCompoundFile(data []byte) (*compoundFile, error) {
+	if len(data) < 512 || !bytes.HasPrefix(data, compoundSignature) {
+		return nil, newError("dbase-ole-parsecompoundfile-1", errors.New("invalid compound file header"))
+	}
+	shift := binary.LittleEndian.Uint16(data[0x1E:])
+	miniShift := binary.LittleEndian.Uint16(data[0x20:])
+	if shift < 7 || shift > 16 || miniShift >= shift {
+		return nil, newError("dbase-ole-parsecompoundfile-2", fmt.Errorf("invalid sector size 2^%v", shift))
+	}
+	cf := &compoundFile{
+		data:           data,
+		sectorSize:     1 << shift,
+		miniSectorSize: 1 << miniShift,
+		cutoff:         uint64(binary.LittleEndian.Uint32(data[0x38:])),
+	}
+	// Collect the FAT sectors from the header and the DIFAT chain
+	fatSectors := make([]uint32, 0)
+	for i := 0; i < 109; i++ {
+		fatSectors = append(fatSectors, binary.LittleEndian.Uint32(data[0x4C+i*4:]))
+	}
+	difat := binary.LittleEndian.Uint32(data[0x44:])
+	for i := 0; difat != endOfChain && difat != freeSector; i++ {
+		sector, err := cf.sector(difat)
+		if err != nil || i > len(data)/cf.sectorSize {
+			return nil, newError("dbase-ole-parsecompoundfile-3", fmt.Errorf("invalid DIFAT chain"))
+		}
+		for j := 0; j < cf.sectorSize/4-1; j++ {
+			fatSectors = append(fatSectors, binary.LittleEndian.Uint32(sector[j*4:]))
+		}
+		difat = binary.LittleEndian.Uint32(sector[cf.sectorSize-4:])
+	}
+	for _, s := range fatSectors {
+		if s == freeSector || s == endOfChain {
+			continue
+		}
+		sector, err := cf.sector(s)
+		if err != nil {
+			return nil, newError("dbase-ole-parsecompoundfile-4", err)
+		}
+		for j := 0; j < cf.sectorSize/4; j++ {
+			cf.fat = append(cf.fat, binary.LittleEndian.Uint32(sector[j*4:]))
+		}
+	}
+	// Read the directory
+	dir, err := cf.read(binary.LittleEndian.Uint32(data[0x30:]), false)
+	if err != nil {
+		return nil, newError("dbase-ole-parsecompoundfile-5", err)
+	}
+	for i := 0; i+128 <= len(dir); i += 128 {
+		entry := dir[i : i+128]
+		length := int(binary.LittleEndian.Uint16(entry[0x40:]))
+		if length > 64 {
+			length = 64
+		}
+		name := make([]uint16, 0, 32)
+		for j := 0; j+1 < length; j += 2 {
+			c := binary.LittleEndian.Uint16(entry[j:])
+			if c == 0 {
+				break
+			}
+			name = append(name, c)
+		}
+		cf.entries = append(cf.entries, compoundEntry{
+			name:  string(utf16.Decode(name)),
+			kind:  entry[0x42],
+			start: binary.LittleEndian.Uint32(entry[0x74:]),
+			size:  binary.LittleEndian.Uint64(entry[0x78:]),
+		})
+	}
+	if len(cf.entries) == 0 || cf.entries[0].kind != 5 {
+		return nil, newError("dbase-ole-parsecompoundfile-6", errors.New("root entry not found"))
+	}
+	// Read the mini stream and mini FAT
+	miniFAT, err := cf.read(binary.LittleEndian.Uint32(data[0x3C:]), false)
+	if err != nil {
+		return nil, newError("dbase-ole-parsecompoundfile-7", err)
+	}
+	for j := 0; j+4 <= len(miniFAT); j += 4 {
+		cf.miniFAT = append(cf.miniFAT, binary.LittleEndian.Uint32(miniFAT[j:]))
+	}
+	root := cf.entries[0]
+	cf.miniStream, err = cf.read(root.start, false)
+	if err != nil {
+		return nil, newError("dbase-ole-parsecompoundfile-8", err)
+	}
+	return cf, nil
+}
+
+// Returns the data of the sector
+func (cf *compoundFile) sector(n uint32) ([]byte, error) {
+	offset := (uint64(n) + 1) * uint64(cf.sectorSize)
+	if offset+uint64(cf.sectorSize) > uint64(len(cf.data)) {
+		return nil, fmt.Errorf("sector %v is out of range", n)
+	}
+	return cf.data[offset : offset+uint64(cf.sectorSize)], nil
+}
+
+// Reads the sector chain starting at the sector, mini sectors are read from the mini stream
+func (cf *compoundFile) read(start uint32, mini bool) ([]byte, error) {
+	fat := cf.fat
+	if mini {
+		fat = cf.miniFAT
+	}
+	buf := new(bytes.Buffer)
+	for n, count := start, 0; n != endOfChain && n != freeSector; count++ {
+		if int(n) >= len(fat) || count > len(fat) {
+			return nil, fmt.Errorf("invalid sector chain at sector %v", n)
+		}
+		if mini {
+			offset := int(n) * cf.miniSectorSize
+			if offset+cf.miniSectorSize > len(cf.miniStream) {
+				return nil, fmt.Errorf("mini sector %v is out of range", n)
+			}
+			buf.Write(cf.miniStream[offset : offset+cf.miniSectorSize])
+		} else {
+			sector, err := cf.sector(n)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(sector)
+		}
+		n = fat[n]
+	}
+	return buf.Bytes(), nil
+}
+
+// Returns the content of the stream with the name
+func (cf *compoundFile) stream(name string) ([]byte, bool, error) {
+	for _, entry := range cf.entries[1:] {
+		if entry.kind != 2 || entry.name != name {
+			continue
+		}
+		var data []byte
+		var err error
+		if entry.size < cf.cutoff {
+			data, err = cf.read(entry.start, true)
+		} else {
+			data, err = cf.read(entry.start, false)
+		}
+		if err != nil {
+			return nil, false, newError("dbase-ole-stream-1", fmt.Errorf("reading stream %q failed with error: %w", name, err))
+		}
+		if entry.size > uint64(len(data)) {
+			return nil, false, newError("dbase-ole-stream-2", fmt.Errorf("stream %q is incomplete", name))
+		}
+		return data[:entry.size], true, nil
+	}
+	return nil, false, nil
+}
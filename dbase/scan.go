@@ -0,0 +1,164 @@
+package dbase
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Number of rows read by a worker at once during a parallel scan
+const scanPartitionSize = 512
+
+// scanPartition is a range of rows read by one worker during a parallel scan
+type scanPartition struct {
+	start uint32        // Position of the first row in the partition
+	end   uint32        // Position after the last row in the partition
+	rows  []*Row        // Rows read if the scan is ordered
+	err   error         // Error occurred while reading the partition
+	done  chan struct{} // Closed as soon as the partition has been read
+}
+
+// ParallelScan reads all rows of the table using the given number of workers and calls fn for every row.
+// The row range is split into partitions that are read by the workers, as all reads are positional
+// the internal row pointer is neither used nor changed.
+// fn is called concurrently from all workers and has to be safe for concurrent use.
+// Deleted rows are passed to fn as well, check Row.Deleted to skip them.
+// The scan stops at the first error returned by fn or when the context is canceled.
+// If workers is less than 1 the number of CPUs is used.
+// The file must not be written while the scan is running.
+func (file *File) ParallelScan(ctx context.Context, workers int, fn func(*Row) error) error {
+	return file.parallelScan(ctx, workers, false, fn)
+}
+
+// ParallelScanOrdered works like ParallelScan but calls fn from a single goroutine in row order.
+// The rows are still read and interpreted concurrently by the workers.
+func (file *File) ParallelScanOrdered(ctx context.Context, workers int, fn func(*Row) error) error {
+	return file.parallelScan(ctx, workers, true, fn)
+}
+
+func (file *File) parallelScan(parent context.Context, workers int, ordered bool, fn func(*Row) error) error {
+	if fn == nil {
+		return newError("dbase-scan-parallelscan-1", fmt.Errorf("no scan function defined"))
+	}
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	count := file.header.RowsCount
+	debugf("Scanning %d rows with %d workers - ordered: %v", count, workers, ordered)
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	var once sync.Once
+	var scanErr error
+	fail := func(err error) {
+		once.Do(func() {
+			scanErr = err
+			cancel()
+		})
+	}
+	// Create the partitions in row order, the queue bounds the number of partitions
+	// kept in memory while waiting to be passed to fn in order
+	jobs := make(chan *scanPartition)
+	queue := make(chan *scanPartition, workers*2)
+	go func() {
+		defer close(jobs)
+		defer close(queue)
+		for start := uint32(0); start < count; start += scanPartitionSize {
+			end := start + scanPartitionSize
+			if end > count || end < start {
+				end = count
+			}
+			partition := &scanPartition{start: start, end: end, done: make(chan struct{})}
+			if ordered {
+				select {
+				case queue <- partition:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case jobs <- partition:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partition := range jobs {
+				if ordered {
+					partition.rows, partition.err = file.scanRows(ctx, partition.start, partition.end, nil)
+					close(partition.done)
+					continue
+				}
+				_, err := file.scanRows(ctx, partition.start, partition.end, fn)
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+	if ordered {
+		for partition := range queue {
+			select {
+			case <-partition.done:
+			case <-ctx.Done():
+				continue
+			}
+			if partition.err != nil {
+				fail(partition.err)
+				continue
+			}
+			for _, row := range partition.rows {
+				err := fn(row)
+				if err != nil {
+					fail(newError("dbase-scan-parallelscan-2", err))
+					break
+				}
+			}
+			partition.rows = nil
+		}
+	}
+	wg.Wait()
+	if scanErr != nil {
+		return newError("dbase-scan-parallelscan-3", scanErr)
+	}
+	if err := parent.Err(); err != nil {
+		return newError("dbase-scan-parallelscan-4", err)
+	}
+	return nil
+}
+
+// Reads and interprets the rows from start to end and passes them to fn
+// If fn is nil the rows are returned instead
+func (file *File) scanRows(ctx context.Context, start uint32, end uint32, fn func(*Row) error) ([]*Row, error) {
+	var rows []*Row
+	if fn == nil {
+		rows = make([]*Row, 0, end-start)
+	}
+	for position := start; position < end; position++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := file.ReadRow(position)
+		if err != nil {
+			return nil, newError("dbase-scan-scanrows-1", err)
+		}
+		row, err := file.bytesToRow(data, position, file.table.projection)
+		if err != nil {
+			return nil, newError("dbase-scan-scanrows-2", err)
+		}
+		if fn == nil {
+			rows = append(rows, row)
+			continue
+		}
+		err = fn(row)
+		if err != nil {
+			return nil, newError("dbase-scan-scanrows-3", err)
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,280 @@
+package dbase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options of a struct field parsed from the dbase tag.
+// The tag has the format `dbase:"NAME,type=N,len=10,dec=2,nullable"`, all parts are optional.
+// If the name is empty the upper case field name is used, a name of "-" skips the field.
+type structTag struct {
+	name        string   // Column name
+	dataType    DataType // Column data type, 0 if not set
+	length      uint8    // Column length, 0 if not set
+	decimals    uint8    // Column decimals
+	hasDecimals bool     // Whether the decimals are set
+	nullable    bool     // Whether the column is nullable
+	skip        bool     // Whether the field is skipped
+}
+
+// A struct field with its index path (for fields of embedded structs) and parsed tag
+type structField struct {
+	field reflect.StructField
+	index []int
+	tag   structTag
+}
+
+// Parses the dbase tag of the struct field
+func parseTag(field reflect.StructField) (structTag, error) {
+	tag := structTag{}
+	parts := strings.Split(field.Tag.Get("dbase"), ",")
+	tag.name = strings.TrimSpace(parts[0])
+	if tag.name == "-" {
+		tag.skip = true
+		return tag, nil
+	}
+	if len(tag.name) == 0 {
+		tag.name = strings.ToUpper(field.Name)
+	}
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch strings.ToLower(key) {
+		case "type":
+			if len(value) != 1 {
+				return tag, newError("dbase-struct-parsetag-1", fmt.Errorf("invalid column type %q at field %v", value, field.Name))
+			}
+			tag.dataType = DataType(strings.ToUpper(value)[0])
+		case "len", "length":
+			length, err := strconv.ParseUint(value, 10, 8)
+			if err != nil {
+				return tag, newError("dbase-struct-parsetag-2", fmt.Errorf("invalid column length %q at field %v", value, field.Name))
+			}
+			tag.length = uint8(length)
+		case "dec", "decimals":
+			decimals, err := strconv.ParseUint(value, 10, 8)
+			if err != nil {
+				return tag, newError("dbase-struct-parsetag-3", fmt.Errorf("invalid column decimals %q at field %v", value, field.Name))
+			}
+			tag.decimals = uint8(decimals)
+			tag.hasDecimals = true
+		case "nullable":
+			tag.nullable = true
+		case "":
+		default:
+			return tag, newError("dbase-struct-parsetag-4", fmt.Errorf("unknown tag option %q at field %v", key, field.Name))
+		}
+	}
+	return tag, nil
+}
+
+// Returns the exported fields of the struct type including the fields of embedded structs
+func structFields(t reflect.Type) ([]structField, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, newError("dbase-struct-structfields-1", fmt.Errorf("expected struct, got %v", t.Kind()))
+	}
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && len(field.Tag.Get("dbase")) == 0 {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && embedded != reflect.TypeOf(time.Time{}) {
+				if embedded != field.Type && !field.IsExported() {
+					return nil, newError("dbase-struct-structfields-4", fmt.Errorf("embedded pointer %v is not exported and can not be allocated, skip it with `dbase:\"-\"`", field.Type))
+				}
+				inner, err := structFields(embedded)
+				if err != nil {
+					return nil, newError("dbase-struct-structfields-2", err)
+				}
+				for _, f := range inner {
+					f.index = append([]int{i}, f.index...)
+					fields = append(fields, f)
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		tag, err := parseTag(field)
+		if err != nil {
+			return nil, newError("dbase-struct-structfields-3", err)
+		}
+		if tag.skip {
+			continue
+		}
+		fields = append(fields, structField{field: field, index: []int{i}, tag: tag})
+	}
+	return fields, nil
+}
+
+// Returns the struct field at the index path. Nil pointers to embedded structs are allocated if alloc is set,
+// otherwise false is returned for fields of nil embedded structs.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// Derives the column definition from the struct field type and tag
+func (f structField) column() (*Column, error) {
+	t := f.field.Type
+	nullable := f.tag.nullable
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	dataType := f.tag.dataType
+	length := f.tag.length
+	if dataType == 0 {
+		switch {
+		case t == reflect.TypeOf(time.Time{}):
+			dataType = DateTime
+		case t == reflect.TypeOf(Decimal{}):
+			dataType = Numeric
+		case t.Kind() == reflect.String:
+			dataType = Character
+		case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			dataType = Memo
+		case t.Kind() == reflect.Bool:
+			dataType = Logical
+		case t.Kind() == reflect.Int8, t.Kind() == reflect.Int16, t.Kind() == reflect.Int32, t.Kind() == reflect.Uint8, t.Kind() == reflect.Uint16:
+			dataType = Integer
+		case t.Kind() == reflect.Int, t.Kind() == reflect.Int64, t.Kind() == reflect.Uint, t.Kind() == reflect.Uint32, t.Kind() == reflect.Uint64:
+			dataType = Numeric
+		case t.Kind() == reflect.Float32, t.Kind() == reflect.Float64:
+			dataType = Double
+			if f.tag.hasDecimals {
+				dataType = Numeric
+			}
+		default:
+			return nil, newError("dbase-struct-column-1", fmt.Errorf("no column type for field %v of type %v, set the type in the dbase tag", f.field.Name, f.field.Type))
+		}
+	}
+	if length == 0 && (dataType == Numeric || dataType == Float) {
+		length = 20
+	}
+	column, err := NewColumn(f.tag.name, dataType, length, f.tag.decimals, nullable)
+	if err != nil {
+		return nil, newError("dbase-struct-column-2", fmt.Errorf("invalid column for field %v: %w", f.field.Name, err))
+	}
+	return column, nil
+}
+
+// Returns the column types a value of the struct field type can be read from
+func (f structField) compatible(dataType DataType) bool {
+	t := f.field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return dataType == Date || dataType == DateTime || dataType == Timestamp
+	case t == reflect.TypeOf(Decimal{}):
+		return dataType == Numeric || dataType == Float || dataType == Currency
+	case t.Kind() == reflect.String:
+		return dataType == Character || dataType == Varchar || dataType == Memo
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return dataType == Memo || dataType == Varbinary || dataType == Varchar || dataType == Blob || dataType == General || dataType == Picture || dataType == NullFlags
+	case t.Kind() == reflect.Bool:
+		return dataType == Logical
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return dataType == Integer || dataType == Autoincrement || dataType == Numeric
+	case t.Kind() == reflect.Float32, t.Kind() == reflect.Float64:
+		return dataType == Numeric || dataType == Float || dataType == Double || dataType == DBaseDouble || dataType == Currency || dataType == Integer || dataType == Autoincrement
+	}
+	return false
+}
+
+// Create a new DBF file with the columns derived from the struct fields.
+// The column name, type, length, decimals and nullability can be set with the dbase tag,
+// for example `dbase:"PRICE,type=N,len=10,dec=2,nullable"`. Pointer fields are nullable.
+// Without a type the column type is derived from the field type:
+//
+//	string >> Character (len required)
+//	[]byte >> Memo
+//	bool >> Logical
+//	int8, int16, int32, uint8, uint16 >> Integer
+//	int, int64, uint, uint32, uint64 >> Numeric (len 20)
+//	float32, float64 >> Double (Numeric if dec is set)
+//	Decimal >> Numeric (len 20)
+//	time.Time >> DateTime
+//
+// The IO defined in the config is used to create the files.
+func NewFromStruct(version FileVersion, config *Config, v interface{}) (*File, error) {
+	fields, err := structFields(reflect.TypeOf(v))
+	if err != nil {
+		return nil, newError("dbase-struct-newfromstruct-1", err)
+	}
+	columns := make([]*Column, 0, len(fields))
+	for _, f := range fields {
+		column, err := f.column()
+		if err != nil {
+			return nil, newError("dbase-struct-newfromstruct-2", err)
+		}
+		columns = append(columns, column)
+	}
+	file, err := New(version, config, columns, 0, config.IO)
+	if err != nil {
+		return nil, newError("dbase-struct-newfromstruct-3", err)
+	}
+	return file, nil
+}
+
+// ValidateStruct checks if the table matches the struct.
+// Every struct field needs a column with a compatible type, type, length, decimals and nullability are compared if set in the tag.
+// Pointer fields need nullable columns.
+// All mismatches are reported in the returned error.
+func (file *File) ValidateStruct(v interface{}) error {
+	fields, err := structFields(reflect.TypeOf(v))
+	if err != nil {
+		return newError("dbase-struct-validatestruct-1", err)
+	}
+	mismatches := make([]string, 0)
+	for _, f := range fields {
+		pos := file.ColumnPosByName(f.tag.name)
+		if pos < 0 {
+			mismatches = append(mismatches, fmt.Sprintf("column %v of field %v not found", f.tag.name, f.field.Name))
+			continue
+		}
+		column := file.table.columns[pos]
+		if f.tag.dataType != 0 && DataType(column.DataType) != f.tag.dataType {
+			mismatches = append(mismatches, fmt.Sprintf("column %v has type %v, expected %v", column.Name(), column.Type(), f.tag.dataType))
+		} else if f.tag.dataType == 0 && !f.compatible(DataType(column.DataType)) {
+			mismatches = append(mismatches, fmt.Sprintf("column %v of type %v is not compatible with field %v of type %v", column.Name(), column.Type(), f.field.Name, f.field.Type))
+		}
+		if f.tag.length != 0 && column.Length != f.tag.length {
+			mismatches = append(mismatches, fmt.Sprintf("column %v has length %v, expected %v", column.Name(), column.Length, f.tag.length))
+		}
+		if f.tag.hasDecimals && column.Decimals != f.tag.decimals {
+			mismatches = append(mismatches, fmt.Sprintf("column %v has %v decimals, expected %v", column.Name(), column.Decimals, f.tag.decimals))
+		}
+		if (f.tag.nullable || f.field.Type.Kind() == reflect.Ptr) && column.Flag&byte(NullableFlag) == 0 {
+			mismatches = append(mismatches, fmt.Sprintf("column %v is not nullable", column.Name()))
+		}
+	}
+	if len(mismatches) > 0 {
+		return newError("dbase-struct-validatestruct-2", errors.New(strings.Join(mismatches, "; ")))
+	}
+	return nil
+}
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 // Configures the file you want to open.
@@ -18,6 +20,8 @@ import (
 type Config struct {
 	Filename                          string            // The filename of the DBF file.
 	Converter                         EncodingConverter // The encoding converter to use.
+	FallbackConverter                 EncodingConverter // The encoding converter to use if the code page mark is interpreted but unknown.
+	ConverterOptions                  ConverterOptions  // Options of the converter created by interpreting or detecting the code page mark.
 	Exclusive                         bool              // If true the file is opened in exclusive mode.
 	Untested                          bool              // If true the file version is not checked.
 	TrimSpaces                        bool              // Trimspaces default value
@@ -26,6 +30,13 @@ type Config struct {
 	WriteLock                         bool              // Whether or not the write operations should lock the record
 	ValidateCodePage                  bool              // Whether or not the code page mark should be validated.
 	InterpretCodePage                 bool              // Whether or not the code page mark should be interpreted. Ignores the defined converter.
+	DetectCodePage                    bool              // If true the encoding of tables without code page mark is detected when interpreting, see DetectEncoding.
+	ExactDecimals                     bool              // If true N (with decimals), F and Y values are read as Decimal instead of float64.
+	DateLayouts                       []string          // Layouts used to parse date strings written to D and T columns, RFC3339 and ISO dates if empty. Strings without zone are in Location.
+	Location                          *time.Location    // Location of D and T values, UTC if nil.
+	EmptyDateNil                      bool              // If true empty D and T values are read as nil instead of the zero time.Time.
+	DateTimeSeconds                   bool              // If true T values are rounded to whole seconds like FoxPro, otherwise milliseconds are kept.
+	Strict                            bool              // If true rows are validated before they are written, see Row.Validate. Numbers exceeding the column length fail instead of being written as overflow (asterisks).
 	IO                                IO                // The IO interface to use.
 }
 
@@ -55,7 +66,9 @@ type MemoHeader struct {
 type Table struct {
 	columns    []*Column       // Columns defined in this table
 	mods       []*Modification // Modification to change values or name of fields
-	rowPointer uint32          // Internal row pointer, can be moved
+	projection map[int]bool    // Positions of the columns interpreted when reading a row, nil for all
+	cursor     *Cursor         // Default cursor used as internal row pointer, can be moved
+	cursorOnce sync.Once       // Creates the default cursor once
 }
 
 // Column is a struct containing the column information
@@ -78,12 +91,16 @@ type Row struct {
 	ByteOffset int64    // Byte offset of the row in the file
 	Deleted    bool     // Deleted flag
 	fields     []*Field // Fields in this row
+	nullFlags  []byte   // Raw _NullFlags data of the row, nil if the table has no null flag column
 }
 
 // Field is a row data field
 type Field struct {
 	column *Column     // Pointer to the column this field belongs to
 	value  interface{} // Value of the field
+	raw    []byte      // Raw data of the field if the value is not interpreted yet
+	row    *Row        // Row the raw data belongs to
+	lossy  bool        // Characters of the value were replaced when it was encoded
 }
 
 // Modification allows to change the column name or value type
@@ -101,6 +118,9 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 	if config.Converter == nil {
 		return nil, errors.New("no converter defined")
 	}
+	if byte(version)&0x07 == 0x04 {
+		return nil, newError("dbase-table-new-1", fmt.Errorf("creating dBase level 7 tables (0x%x) is not supported", byte(version)))
+	}
 	file := &File{
 		config: config,
 		io:     io,
@@ -115,9 +135,11 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 		},
 		table: &Table{
 			columns: make([]*Column, 0),
+			mods:    make([]*Modification, len(columns)),
 		},
 		dbaseMutex: &sync.Mutex{},
 		memoMutex:  &sync.Mutex{},
+		seekMutex:  &sync.Mutex{},
 	}
 	debugf("Creating new DBF file: %v - type: %v - year: %v - month: %v - day: %v - first row: %v - row length: %v - code page: %v - columns: %v", config.Filename, file.header.FileType, file.header.Year, file.header.Month, file.header.Day, file.header.FirstRow, file.header.RowLength, file.header.CodePage, len(columns))
 	// Determines how many bytes are needed for the _NullFlag field if needed
@@ -125,7 +147,11 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 	// Check if we need a memo file
 	memoField := false
 	for _, column := range columns {
-		if column.DataType == byte(Memo) {
+		// FoxPro can not open tables with dBase level 7 types, autoincrement columns are Integer columns with AutoincrementFlag
+		if DataType(column.DataType).level7() {
+			return nil, newError("dbase-table-new-2", fmt.Errorf("column %v has the dBase level 7 type %v which is not supported in tables of version 0x%x", column.Name(), DataType(column.DataType), byte(version)))
+		}
+		if DataType(column.DataType).memo() {
 			memoField = true
 			file.header.TableFlags = byte(MemoFlag)
 		}
@@ -145,8 +171,12 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 	}
 	// If there are memo fields, add the memo header
 	if memoField {
+		if memoBlockSize == 0 {
+			memoBlockSize = 64
+		}
+		// The first blocks are occupied by the 512 bytes memo file header
 		file.memoHeader = &MemoHeader{
-			NextFree:  0,
+			NextFree:  uint32((512 + int(memoBlockSize) - 1) / int(memoBlockSize)),
 			Unused:    [2]byte{0x00, 0x00},
 			BlockSize: memoBlockSize,
 		}
@@ -198,7 +228,8 @@ func New(version FileVersion, config *Config, columns []*Column, memoBlockSize u
 	return file, nil
 }
 
-// Create a new table column with the given name, type and length
+// Create a new table column with the given name, type and length.
+// The dBase level 7 types Autoincrement, Timestamp and DBaseDouble are rejected by New, use Integer with AutoincrementFlag for autoincrement columns.
 func NewColumn(name string, dataType DataType, length uint8, decimals uint8, nullable bool) (*Column, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no column name defined")
@@ -217,7 +248,7 @@ func NewColumn(name string, dataType DataType, length uint8, decimals uint8, nul
 		Step:      uint16(0),
 		Reserved:  [7]byte{},
 	}
-	copy(column.FieldName[:], []byte(strings.ToUpper(name))[:11])
+	copy(column.FieldName[:], strings.ToUpper(name))
 	debugf("Creating new column: %v - type: %v - length: %v - decimals: %v - nullable: %v - position: %v - flag: %v", name, dataType, length, decimals, nullable, column.Position, column.Flag)
 	// Set the appropriate flag for nullable fields
 	if nullable {
@@ -268,9 +299,14 @@ func NewColumn(name string, dataType DataType, length uint8, decimals uint8, nul
 		column.Length = length
 	case Logical:
 		column.Length = 1
-	case Integer, Memo:
+	case Integer, Memo, General, Picture, Blob:
 		column.Length = 4
-	case Currency, Date, DateTime, Double:
+	case Autoincrement:
+		column.Length = 4
+		column.Flag = byte(AutoincrementFlag)
+		column.Next = 1
+		column.Step = 1
+	case Currency, Date, DateTime, Double, Timestamp, DBaseDouble:
 		column.Length = 8
 	default:
 		return nil, newError("dbase-table-newcolumn-12", fmt.Errorf("invalid data type %v specified", dataType))
@@ -304,19 +340,24 @@ func (h *Header) FileSize() int64 {
 	return 296 + int64(h.ColumnsCount()*32) + int64(h.RowsCount*uint32(h.RowLength))
 }
 
+// Returns if the table is a dBase level 7 table (0x04 or 0x8C)
+func (h *Header) Level7() bool {
+	return h.FileType&0x07 == 0x04
+}
+
 // Returns if the internal row pointer is at end of file
 func (file *File) EOF() bool {
-	return file.table.rowPointer >= file.header.RowsCount
+	return file.Cursor().EOF()
 }
 
 // Returns if the internal row pointer is before first row
 func (file *File) BOF() bool {
-	return file.table.rowPointer == 0
+	return file.Cursor().BOF()
 }
 
 // Returns the current row pointer position
 func (file *File) Pointer() uint32 {
-	return file.table.rowPointer
+	return file.Cursor().Pointer()
 }
 
 // Returns the dBase table file header struct for inspecting
@@ -334,6 +375,12 @@ func (file *File) Columns() []*Column {
 	return file.table.columns
 }
 
+// Returns the _NullFlags column or nil if the table has none.
+// The column is not part of Columns, the raw null flags of a row are returned by Row.NullFlags.
+func (file *File) NullFlagColumn() *Column {
+	return file.nullFlagColumn
+}
+
 // Returns the requested column
 func (file *File) Column(pos int) *Column {
 	if pos < 0 || pos >= len(file.table.columns) {
@@ -358,9 +405,10 @@ func (file *File) ColumnNames() []string {
 }
 
 // Returns the column position of a column by name or -1 if not found.
+// The long names of dBase level 7 columns are matched too, see LongName.
 func (file *File) ColumnPosByName(colname string) int {
 	for i := 0; i < len(file.table.columns); i++ {
-		if file.table.columns[i].Name() == colname {
+		if file.table.columns[i].Name() == colname || file.LongName(file.table.columns[i]) == colname {
 			return i
 		}
 	}
@@ -377,6 +425,43 @@ func (file *File) ColumnPos(column *Column) int {
 	return -1
 }
 
+// Select defines the columns that are interpreted when a row is read.
+// The values of all other columns are interpreted on first access, so memo columns
+// that are never accessed are not read from the memo file.
+// Calling Select without column names restores interpreting all columns.
+func (file *File) Select(columns ...string) error {
+	if len(columns) == 0 {
+		debugf("Selecting all columns")
+		file.table.projection = nil
+		return nil
+	}
+	projection := make(map[int]bool, len(columns))
+	for _, name := range columns {
+		pos := file.ColumnPosByName(name)
+		if pos < 0 {
+			return newError("dbase-table-select-1", fmt.Errorf("column '%s' not found", name))
+		}
+		projection[pos] = true
+	}
+	debugf("Selecting columns: %v", columns)
+	file.table.projection = projection
+	return nil
+}
+
+// Returns the names of the selected columns, all column names if no selection is defined
+func (file *File) Selected() []string {
+	if file.table.projection == nil {
+		return file.ColumnNames()
+	}
+	names := make([]string, 0, len(file.table.projection))
+	for i, column := range file.table.columns {
+		if file.table.projection[i] {
+			names = append(names, column.Name())
+		}
+	}
+	return names
+}
+
 // SetColumnModification sets a modification for a column
 func (file *File) SetColumnModification(position int, mod *Modification) {
 	// Skip if position is out of range
@@ -436,6 +521,91 @@ func (file *File) Rows(skipInvalid bool, skipDeleted bool) ([]*Row, error) {
 	return rows, nil
 }
 
+// SearchFunc returns all not deleted rows for which the predicate returns true.
+// The rows are read one by one and only the matching rows are kept in memory, deleted rows are skipped before the predicate is called.
+// If column names are passed, only these columns are interpreted before the predicate is called
+// and the complete row is interpreted only if the predicate matched.
+// This avoids reading memo data and converting values of rows that do not match.
+// The internal row pointer is not changed by the search.
+func (file *File) SearchFunc(predicate func(*Row) bool, columns ...string) ([]*Row, error) {
+	if predicate == nil {
+		return nil, newError("dbase-table-searchfunc-1", fmt.Errorf("no predicate defined"))
+	}
+	var positions map[int]bool
+	if len(columns) > 0 {
+		positions = make(map[int]bool, len(columns))
+		for _, name := range columns {
+			pos := file.ColumnPosByName(name)
+			if pos < 0 {
+				return nil, newError("dbase-table-searchfunc-2", fmt.Errorf("column '%s' not found", name))
+			}
+			positions[pos] = true
+		}
+	}
+	rows := make([]*Row, 0)
+	for i := uint32(0); i < file.header.RowsCount; i++ {
+		data, err := file.ReadRow(i)
+		if err != nil {
+			return nil, newError("dbase-table-searchfunc-3", err)
+		}
+		row, err := file.bytesToRow(data, i, positions)
+		if err != nil {
+			return nil, newError("dbase-table-searchfunc-4", err)
+		}
+		if row.Deleted || !predicate(row) {
+			continue
+		}
+		debugf("Found matching row at position: %d", i)
+		// Interpret the remaining (selected) columns if only a subset of columns was interpreted
+		if positions != nil {
+			for pos, field := range row.fields {
+				if field.Loaded() || (file.table.projection != nil && !file.table.projection[pos]) {
+					continue
+				}
+				err = field.load()
+				if err != nil {
+					return nil, newError("dbase-table-searchfunc-5", err)
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// SearchRegex returns all not deleted rows where the value of the given column matches the regular expression.
+// Only the searched column is interpreted before matching, non string values are matched by their
+// default string representation. String values are trimmed if TrimSpaces is configured.
+func (file *File) SearchRegex(column string, expression *regexp.Regexp) ([]*Row, error) {
+	if expression == nil {
+		return nil, newError("dbase-table-searchregex-1", fmt.Errorf("no regular expression defined"))
+	}
+	pos := file.ColumnPosByName(column)
+	if pos < 0 {
+		return nil, newError("dbase-table-searchregex-2", fmt.Errorf("column '%s' not found", column))
+	}
+	debugf("Searching for expression: %v in field: %s", expression, column)
+	rows, err := file.SearchFunc(func(row *Row) bool {
+		switch val := row.Value(pos).(type) {
+		case nil:
+			return false
+		case []byte:
+			return expression.Match(val)
+		case string:
+			if file.config.TrimSpaces {
+				val = strings.TrimSpace(val)
+			}
+			return expression.MatchString(val)
+		default:
+			return expression.MatchString(fmt.Sprintf("%v", val))
+		}
+	}, column)
+	if err != nil {
+		return nil, newError("dbase-table-searchregex-3", err)
+	}
+	return rows, nil
+}
+
 // Reads the row and increments the row pointer by one
 func (file *File) Next() (*Row, error) {
 	row, err := file.Row()
@@ -446,13 +616,26 @@ func (file *File) Next() (*Row, error) {
 	return row, err
 }
 
-// Returns the requested row at file.rowPointer.
+// Decrements the row pointer by one and reads the row
+func (file *File) Prev() (*Row, error) {
+	if file.BOF() {
+		return nil, newError("dbase-table-prev-1", ErrBOF)
+	}
+	file.Skip(-1)
+	row, err := file.Row()
+	if err != nil {
+		return nil, newError("dbase-table-prev-2", err)
+	}
+	return row, err
+}
+
+// Returns the requested row at the internal row pointer
 func (file *File) Row() (*Row, error) {
-	data, err := file.ReadRow(file.table.rowPointer)
+	row, err := file.Cursor().Row()
 	if err != nil {
 		return nil, newError("dbase-table-row-1", err)
 	}
-	return file.BytesToRow(data)
+	return row, nil
 }
 
 // Returns a new Row struct with the same column structure as the dbf and the next row pointer
@@ -525,7 +708,7 @@ func (row *Row) Values() []interface{} {
 	values := make([]interface{}, 0)
 	for _, field := range row.fields {
 		if field != nil {
-			values = append(values, field.value)
+			values = append(values, field.GetValue())
 		}
 	}
 	return values
@@ -533,7 +716,7 @@ func (row *Row) Values() []interface{} {
 
 // Returns the value of a row at the given position
 func (row *Row) Value(pos int) interface{} {
-	return row.fields[pos].value
+	return row.fields[pos].GetValue()
 }
 
 // Returns the value of a row at the given column name
@@ -550,6 +733,22 @@ func (row *Row) Fields() []*Field {
 	return row.fields
 }
 
+// Returns the raw _NullFlags data of the row, nil if the table has no null flag column
+func (row *Row) NullFlags() []byte {
+	return row.nullFlags
+}
+
+// LossyFields returns the fields with lossy values, see Field.Lossy
+func (row *Row) LossyFields() []*Field {
+	fields := make([]*Field, 0)
+	for _, field := range row.fields {
+		if field.Lossy() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
 // Returns the field of a row by position or nil if not found
 func (row *Row) Field(pos int) *Field {
 	if pos < 0 || pos >= len(row.fields) {
@@ -569,12 +768,90 @@ func (field *Field) SetValue(value interface{}) error {
 		return newError("dbase-table-setvalue-1", fmt.Errorf("field is not defined by table"))
 	}
 	field.value = value
+	field.raw = nil
+	field.lossy = false
 	return nil
 }
 
-// Value returns the field value
+// GetValue returns the field value
+// If the value has not been interpreted yet (see Select) it is interpreted now.
+// If the interpretation fails nil is returned, use Value to receive the error.
 func (field Field) GetValue() interface{} {
-	return field.value
+	if field.raw == nil {
+		return field.value
+	}
+	value, err := field.original().Value()
+	if err != nil {
+		errorf("interpreting field %v failed with error: %v", field.Name(), err)
+		return nil
+	}
+	return value
+}
+
+// Value returns the field value or the error of the interpretation
+// If the value has not been interpreted yet (see Select) it is interpreted now.
+func (field *Field) Value() (interface{}, error) {
+	if field.raw != nil {
+		err := field.load()
+		if err != nil {
+			return nil, newError("dbase-table-value-1", err)
+		}
+	}
+	return field.value, nil
+}
+
+// Returns the field of the row the field was copied from, so the interpreted value is kept
+func (field Field) original() *Field {
+	if field.row != nil {
+		for _, f := range field.row.fields {
+			if f != nil && f.column == field.column {
+				return f
+			}
+		}
+	}
+	return &field
+}
+
+// Returns if the value of the field has been interpreted
+func (field *Field) Loaded() bool {
+	return field.raw == nil
+}
+
+// Lossy returns if characters of the value were replaced when it was encoded (see UnencodablePolicy),
+// if a number exceeding the column length was written as overflow (see Config.Strict)
+// or if the value contains characters that could not be decoded (U+FFFD).
+func (field *Field) Lossy() bool {
+	if field.lossy {
+		return true
+	}
+	s, ok := field.GetValue().(string)
+	return ok && strings.ContainsRune(s, utf8.RuneError)
+}
+
+// Interprets the raw data of the field
+func (field *Field) load() error {
+	debugf("Interpreting field %v on access", field.Name())
+	val, err := field.row.handle.interpret(field.raw, field.column, field.row.nullFlags)
+	if err != nil {
+		return newError("dbase-table-field-load-1", err)
+	}
+	field.value = val
+	field.raw = nil
+	return nil
+}
+
+// Load interprets the values of all fields that have not been interpreted yet
+func (row *Row) Load() error {
+	for _, field := range row.fields {
+		if field.raw == nil {
+			continue
+		}
+		err := field.load()
+		if err != nil {
+			return newError("dbase-table-row-load-1", err)
+		}
+	}
+	return nil
 }
 
 // Name returns the field name
@@ -594,10 +871,18 @@ func (field Field) Column() *Column {
 
 // Converts raw row data to a Row struct
 // If the data points to a memo (FPT) file this file is also read
+// If columns are selected (see Select) only these are interpreted, all other fields are interpreted on access
 func (file *File) BytesToRow(data []byte) (*Row, error) {
+	return file.bytesToRow(data, file.Cursor().Pointer(), file.table.projection)
+}
+
+// Converts raw row data to a Row struct, only the columns at the given positions are interpreted.
+// All other fields are interpreted on first access. If positions is nil all columns are interpreted.
+func (file *File) bytesToRow(data []byte, position uint32, positions map[int]bool) (*Row, error) {
 	debugf("Converting row data (%d bytes) to row struct...", len(data))
 	rec := &Row{}
-	rec.Position = file.table.rowPointer
+	rec.Position = position
+	rec.ByteOffset = int64(file.header.FirstRow) + int64(position)*int64(file.header.RowLength)
 	rec.handle = file
 	rec.fields = make([]*Field, 0)
 	if len(data) < int(file.header.RowLength) {
@@ -608,11 +893,23 @@ func (file *File) BytesToRow(data []byte) (*Row, error) {
 	if !rec.Deleted && Marker(data[0]) != Active {
 		return nil, newError("dbase-table-bytestorow-2", fmt.Errorf("invalid row data, no delete flag found at beginning of row"))
 	}
+	if file.nullFlagColumn != nil {
+		rec.nullFlags = data[file.nullFlagColumn.Position : file.nullFlagColumn.Position+uint32(file.nullFlagColumn.Length)]
+	}
 	// deleted flag already read
 	offset := uint16(1)
 	for i := 0; i < int(file.ColumnsCount()); i++ {
 		column := file.table.columns[i]
-		val, err := file.Interpret(data[offset:offset+uint16(column.Length)], file.table.columns[i])
+		if positions != nil && !positions[i] {
+			rec.fields = append(rec.fields, &Field{
+				column: column,
+				raw:    data[offset : offset+uint16(column.Length)],
+				row:    rec,
+			})
+			offset += uint16(column.Length)
+			continue
+		}
+		val, err := file.interpret(data[offset:offset+uint16(column.Length)], file.table.columns[i], rec.nullFlags)
 		if err != nil {
 			return rec, newError("dbase-table-bytestorow-3", err)
 		}
@@ -628,6 +925,12 @@ func (file *File) BytesToRow(data []byte) (*Row, error) {
 // Converts the row back to raw dbase data
 func (row *Row) ToBytes() ([]byte, error) {
 	debugf("Converting row %v to row data (%d bytes)...", row.Position, row.handle.header.RowLength)
+	if row.handle.config.Strict {
+		err := row.Validate()
+		if err != nil {
+			return nil, newError("dbase-table-rowtobytes-2", err)
+		}
+	}
 	data := make([]byte, row.handle.header.RowLength)
 	// a row should start with te delete flag, a space ACTIVE(0x20) or DELETED(0x2A)
 	if row.Deleted {
@@ -639,14 +942,28 @@ func (row *Row) ToBytes() ([]byte, error) {
 	offset := uint16(1)
 	varPos := 0
 	nullFlag := make([]byte, 1)
+	if row.handle.nullFlagColumn != nil {
+		nullFlag = make([]byte, row.handle.nullFlagColumn.Length)
+	}
 	for _, field := range row.fields {
-		val, err := row.handle.GetRepresentation(field, false)
-		if err != nil {
-			return nil, newError("dbase-table-rowtobytes-1", err)
+		var val []byte
+		var err error
+		if field.raw != nil && field.column.DataType != byte(Varchar) && field.column.DataType != byte(Varbinary) {
+			// Not interpreted values are written back unchanged, memo addresses stay valid
+			val = field.raw
+		} else {
+			val, err = row.handle.GetRepresentation(field, false)
+			if err != nil {
+				return nil, newError("dbase-table-rowtobytes-1", err)
+			}
 		}
 		// Get null and length if variable length field
 		if field.column.DataType == byte(Varbinary) || field.column.DataType == byte(Varchar) {
+			if field.raw == nil && isNilValue(field.value) {
+				val = nil
+			}
 			length := len(val)
+			nullable := field.column.Flag == byte(NullableFlag) || field.column.Flag == byte(NullableFlag|BinaryFlag)
 			// Not null and not full size
 			if length < int(field.column.Length) && length > 0 {
 				debugf("Variable length field %v is not null and not full size (%v < %v)", field.column.Name(), length, field.column.Length)
@@ -655,19 +972,20 @@ func (row *Row) ToBytes() ([]byte, error) {
 				copy(buf, val)
 				buf[field.column.Length-1] = byte(length)
 				val = buf
-				// Set full size flag
-				byteIndex := varPos / 8
-				bitIndex := varPos % 8
-				nullFlag[byteIndex] = setNthBit(nullFlag[byteIndex], bitIndex)
-			} else if length == 0 { // Null
+				// Set variable length flag
+				nullFlag[varPos/8] = setNthBit(nullFlag[varPos/8], varPos%8)
+			} else if length == 0 && nullable { // Null
 				debugf("Variable length field %v is null", field.column.Name())
 				// Set null flag
-				byteIndex := varPos / 8
-				bitIndex := varPos % 8
-				nullFlag[byteIndex] = setNthBit(nullFlag[byteIndex], bitIndex+1)
+				nullFlag[(varPos+1)/8] = setNthBit(nullFlag[(varPos+1)/8], (varPos+1)%8)
+			} else if length == 0 { // Empty but not nullable
+				debugf("Variable length field %v is empty", field.column.Name())
+				// Set variable length flag, the length byte stays 0
+				val = make([]byte, field.column.Length)
+				nullFlag[varPos/8] = setNthBit(nullFlag[varPos/8], varPos%8)
 			}
 			// Increase variable field in nullFlag position
-			if field.column.Flag == byte(NullableFlag) || field.column.Flag == byte(NullableFlag|BinaryFlag) {
+			if nullable {
 				varPos += 2
 			} else {
 				varPos++
@@ -738,6 +1056,7 @@ func (row *Row) ToJSON() ([]byte, error) {
 // Converts a row to a struct.
 // The struct must have the same field names as the columns in the table or the dbase tag must be set.
 // The dbase tag can be used to name the field. For example: `dbase:"my_field_name"`
+// Pointer fields are set to nil for null values.
 func (row *Row) ToStruct(v interface{}) error {
 	rt := reflect.TypeOf(v)
 	if rt.Kind() != reflect.Ptr {
@@ -748,9 +1067,16 @@ func (row *Row) ToStruct(v interface{}) error {
 	if err != nil {
 		return newError("dbase-table-struct-2", err)
 	}
-	tags := structTags(v)
+	tags, err := structTags(v)
+	if err != nil {
+		return newError("dbase-table-tostruct-3", err)
+	}
 	for k, val := range m {
-		err := setStructField(tags, v, k, val)
+		f, ok := tags[k]
+		if !ok {
+			continue
+		}
+		err := setStructField(v, f, val)
 		if err != nil {
 			return newError("dbase-table-tostruct-2", err)
 		}
@@ -758,25 +1084,29 @@ func (row *Row) ToStruct(v interface{}) error {
 	return nil
 }
 
-// Converts a map of interfaces into the row representation
+// Converts a map of interfaces into the row representation.
+// Values are converted to the Go type of the column: all integer and float kinds, json.Number,
+// driver.Valuer values like sql.NullString and pointers are accepted, strings are parsed as date
+// with Config.DateLayouts for D and T columns. Conversions that would lose data return an error.
 func (file *File) RowFromMap(m map[string]interface{}) (*Row, error) {
 	debugf("Converting map to row...")
 	row := file.NewRow()
 	for i := range row.fields {
 		field := &Field{column: file.table.columns[i]}
 		mod := file.table.mods[i]
-		if mod != nil {
-			if len(mod.ExternalKey) != 0 {
-				if val, ok := m[mod.ExternalKey]; ok {
-					debugf("Resolving external key %v for field %v due to modification", mod.ExternalKey, field.Name())
-					field.value = val
-					row.fields[i] = field
-					continue
-				}
+		val, ok := m[field.Name()]
+		if mod != nil && len(mod.ExternalKey) != 0 {
+			if v, found := m[mod.ExternalKey]; found {
+				debugf("Resolving external key %v for field %v due to modification", mod.ExternalKey, field.Name())
+				val, ok = v, true
 			}
 		}
-		if val, ok := m[field.Name()]; ok {
-			field.value = val
+		if ok {
+			value, err := file.coerce(val, field.column)
+			if err != nil {
+				return nil, newError("dbase-file-rowfrommap-2", fmt.Errorf("converting value of column %v failed with error: %w", field.Name(), err))
+			}
+			field.value = value
 		}
 		row.fields[i] = field
 	}
@@ -805,28 +1135,37 @@ func (file *File) RowFromJSON(j []byte) (*Row, error) {
 // Converts a struct into the row representation
 // The struct must have the same field names as the columns in the table or the dbase tag must be set.
 // The dbase tag can be used to name the field. For example: `dbase:"my_field_name"`
+// Nil pointer fields are written as null values.
 func (file *File) RowFromStruct(v interface{}) (*Row, error) {
 	debugf("Converting struct to row...")
 	m := make(map[string]interface{})
-	rt := reflect.TypeOf(v)
-	if rt.Kind() == reflect.Ptr {
-		rt = rt.Elem()
+	fields, err := structFields(reflect.TypeOf(v))
+	if err != nil {
+		return nil, newError("dbase-table-fromstruct-1", err)
 	}
 	rv := reflect.ValueOf(v)
 	if rv.Kind() == reflect.Ptr {
 		rv = rv.Elem()
 	}
-	for i := 0; i < rt.NumField(); i++ {
-		field := rt.Field(i)
-		tag := field.Tag.Get("dbase")
-		if len(tag) == 0 {
-			tag = field.Name
+	for _, f := range fields {
+		value, ok := fieldByIndex(rv, f.index, false)
+		if !ok {
+			// Fields of nil embedded structs are null
+			m[f.tag.name] = nil
+			continue
 		}
-		m[tag] = rv.Field(i).Interface()
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				m[f.tag.name] = nil
+				continue
+			}
+			value = value.Elem()
+		}
+		m[f.tag.name] = value.Interface()
 	}
 	row, err := file.RowFromMap(m)
 	if err != nil {
-		return nil, newError("dbase-table-fromstruct-1", err)
+		return nil, newError("dbase-table-fromstruct-2", err)
 	}
 	return row, nil
 }
@@ -0,0 +1,22 @@
+package dbase
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestNewLevel7Types(t *testing.T) {
+	file := createTestTable(t, FoxProAutoincrement, []*Column{mustColumn(t, "NAME", Character, 10, 0)}, nil)
+	for _, dataType := range []DataType{Autoincrement, Timestamp, DBaseDouble} {
+		column := mustColumn(t, "VALUE", dataType, 0, 0)
+		_, err := New(FoxProAutoincrement, &Config{Filename: "LEVEL7.DBF", Converter: NewDefaultConverter(charmap.Windows1252)}, []*Column{column}, 0, nil)
+		if err == nil {
+			t.Errorf("creating a FoxPro table with a %v column succeeded", dataType)
+		}
+		err = file.AlterTable(AddColumn(column))
+		if err == nil {
+			t.Errorf("adding a %v column to a FoxPro table succeeded", dataType)
+		}
+	}
+}
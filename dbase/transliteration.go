@@ -0,0 +1,94 @@
+package dbase
+
+import (
+	"errors"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// UnencodablePolicy defines how characters are written that the encoding can not represent
+type UnencodablePolicy uint8
+
+const (
+	UnencodableError         UnencodablePolicy = iota // Encoding fails with an error naming the character
+	UnencodableReplace                                // The character is replaced by '?'
+	UnencodableTransliterate                          // The character is replaced by a similar one (e.g. ł => l, € => EUR), '?' if there is none
+)
+
+// Transliterations of characters without a base letter the encoding may represent
+var transliterations = map[rune]string{
+	'ß': "ss", 'ẞ': "SS", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D", 'þ': "th", 'Þ': "TH", 'ı': "i", 'ħ': "h", 'Ħ': "H",
+	'€': "EUR", '£': "GBP", '¥': "JPY", '©': "(C)", '®': "(R)", '™': "TM", '°': "o",
+	'‘': "'", '’': "'", '‚': ",", '“': "\"", '”': "\"", '„': "\"", '«': "\"", '»': "\"", '‹': "<", '›': ">",
+	'–': "-", '—': "-", '‐': "-", '−': "-", '…': "...", '•': "*", '·': ".", '×': "x", '÷': "/",
+	'\u00A0': " ", '\u2009': " ", '\u202F': " ", '½': "1/2", '¼': "1/4", '¾': "3/4",
+}
+
+// Returns the replacement candidates of an unencodable rune, the base letter without diacritics first
+func transliterate(r rune) []string {
+	candidates := make([]string, 0, 2)
+	base := make([]rune, 0, 1)
+	for _, d := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, d) {
+			base = append(base, d)
+		}
+	}
+	if len(base) > 0 && string(base) != string(r) {
+		candidates = append(candidates, string(base))
+	}
+	if t, ok := transliterations[r]; ok {
+		candidates = append(candidates, t)
+	}
+	return candidates
+}
+
+// Returns the encoded replacement of an unencodable rune according to the policy
+func (c DefaultConverter) replacement(r rune) []byte {
+	if c.options.Unencodable == UnencodableTransliterate {
+		for _, candidate := range transliterate(r) {
+			encoded, err := c.encoding.NewEncoder().Bytes([]byte(candidate))
+			if err == nil {
+				return encoded
+			}
+		}
+	}
+	return []byte{'?'}
+}
+
+// Applies the unencodable policy of the converter to the runes the encoder can not encode
+type unencodableHandler struct {
+	encoder   transform.Transformer
+	converter DefaultConverter
+	replaced  int // Number of replaced runes
+}
+
+func (h *unencodableHandler) Reset() {
+	h.encoder.Reset()
+}
+
+func (h *unencodableHandler) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for {
+		n, m, err := h.encoder.Transform(dst[nDst:], src[nSrc:], atEOF)
+		nDst += n
+		nSrc += m
+		if err == nil || errors.Is(err, transform.ErrShortDst) || errors.Is(err, transform.ErrShortSrc) || nSrc >= len(src) {
+			return nDst, nSrc, err
+		}
+		r, size := utf8.DecodeRune(src[nSrc:])
+		if h.converter.options.Unencodable == UnencodableError {
+			return nDst, nSrc, unencodableError{r}
+		}
+		replacement := h.converter.replacement(r)
+		if len(dst)-nDst < len(replacement) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], replacement)
+		nSrc += size
+		h.replaced++
+	}
+}
@@ -0,0 +1,118 @@
+package dbase
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes why the value of a field can not be written without loss
+type FieldError struct {
+	Column string      // Name of the column
+	Value  interface{} // The invalid value
+	Reason string      // Description of the problem
+}
+
+// ValidationError is returned by Row.Validate and lists every invalid field of the row
+type ValidationError struct {
+	Position uint32       // Position of the row
+	Fields   []FieldError // The invalid fields
+}
+
+// Error returns all invalid fields as one message
+func (e ValidationError) Error() string {
+	reasons := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		reasons = append(reasons, fmt.Sprintf("%v: %v", f.Column, f.Reason))
+	}
+	return fmt.Sprintf("row %v is invalid: %v", e.Position, strings.Join(reasons, "; "))
+}
+
+// Validate checks if all field values of the row can be written without loss.
+// Reported are values that have the wrong Go type, overflow the column length, lose decimals
+// and nil values of columns that are not nullable (autoincrement, memo and date columns excepted).
+// If the row is invalid a ValidationError describing every invalid field is returned.
+// If Config.Strict is set rows are validated before they are written.
+func (row *Row) Validate() error {
+	invalid := ValidationError{Position: row.Position}
+	for _, field := range row.fields {
+		if field.raw != nil {
+			// Not interpreted values are written back unchanged
+			continue
+		}
+		reason := row.handle.validateField(field)
+		if len(reason) > 0 {
+			invalid.Fields = append(invalid.Fields, FieldError{Column: field.Name(), Value: field.value, Reason: reason})
+		}
+	}
+	if len(invalid.Fields) > 0 {
+		return newError("dbase-validate-validate-1", invalid)
+	}
+	return nil
+}
+
+// Returns why the field value can not be written without loss, empty if the value is valid
+func (file *File) validateField(field *Field) string {
+	column := field.column
+	dataType := DataType(column.DataType)
+	if isNilValue(field.value) {
+		nullable := column.Flag&byte(NullableFlag) != 0
+		autoincrement := column.Flag&byte(AutoincrementFlag) != 0
+		switch {
+		case nullable, autoincrement, dataType == Autoincrement, dataType == Memo, dataType == Blob, dataType == General, dataType == Picture:
+			return ""
+		case dataType == Date, dataType == DateTime, dataType == Timestamp:
+			// Written as empty date
+			return ""
+		}
+		return "column is not nullable"
+	}
+	value, err := file.coerce(field.value, column)
+	if err != nil {
+		return err.Error()
+	}
+	switch dataType {
+	case Memo, General, Picture, Blob:
+		switch value.(type) {
+		case string, []byte, OLEObject, io.Reader:
+			return ""
+		}
+		return fmt.Sprintf("invalid data type %T, expected string, []byte or io.Reader", value)
+	}
+	raw, err := file.GetRepresentation(field, true)
+	if err != nil {
+		return err.Error()
+	}
+	if (dataType == Numeric || dataType == Float) && field.lossy {
+		// The number has been replaced by the overflow value
+		return fmt.Sprintf("value %v exceeds the column length %v", value, column.Length)
+	}
+	switch dataType {
+	case Character, Varchar, Varbinary:
+		if len(raw) > int(column.Length) {
+			return fmt.Sprintf("value length %v exceeds the column length %v", len(raw), column.Length)
+		}
+	case Numeric, Float, Currency:
+		decimals := column.Decimals
+		if dataType == Currency {
+			decimals = 4
+		}
+		var d Decimal
+		switch v := value.(type) {
+		case float64:
+			d, err = ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+			if err != nil {
+				return err.Error()
+			}
+		case Decimal:
+			d = v
+		default:
+			return ""
+		}
+		if d.Round(decimals).Cmp(d) != 0 {
+			return fmt.Sprintf("value %v loses decimals, the column has %v decimals", d, decimals)
+		}
+	}
+	return ""
+}
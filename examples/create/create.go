@@ -126,4 +126,27 @@ func main() {
 
 		fmt.Printf("Row at position: %v => %v \n", row.Position, name)
 	}
+
+	// Widen the name column and add a new column, existing rows are migrated
+	wideNameCol, err := dbase.NewColumn("Name", dbase.Character, 40, 0, false)
+	if err != nil {
+		panic(dbase.GetErrorTrace(err))
+	}
+
+	activeCol, err := dbase.NewColumn("Active", dbase.Logical, 0, 0, false)
+	if err != nil {
+		panic(dbase.GetErrorTrace(err))
+	}
+
+	err = file.AlterTable(
+		dbase.ModifyColumn("NAME", wideNameCol),
+		dbase.AddColumn(activeCol),
+	)
+	if err != nil {
+		panic(dbase.GetErrorTrace(err))
+	}
+
+	for _, column := range file.Columns() {
+		fmt.Printf("Name: %v - Type: %v - Length: %v \n", column.Name(), column.Type(), column.Length)
+	}
 }
@@ -1,54 +1,184 @@
-package main 
-
-import "time"
-
-// Auto generated table struct: employees 
-type EMPLOYEES struct {
-	EMPLOYEEID   int32        `dbase:"EMPLOYEEID"`
-	DEPARTMENT   string       `dbase:"DEPARTMENT"`
-	SOCIALSECU   string       `dbase:"SOCIALSECU"`
-	EMPLOYEENU   string       `dbase:"EMPLOYEENU"`
-	FIRSTNAME    string       `dbase:"FIRSTNAME"`
-	LASTNAME     string       `dbase:"LASTNAME"`
-	TITLE        string       `dbase:"TITLE"`
-	EMAILNAME    string       `dbase:"EMAILNAME"`
-	EXTENSION    string       `dbase:"EXTENSION"`
-	ADDRESS      []uint8      `dbase:"ADDRESS"`
-	CITY         string       `dbase:"CITY"`
-	STATEORPRO   string       `dbase:"STATEORPRO"`
-	POSTALCODE   string       `dbase:"POSTALCODE"`
-	COUNTRY      string       `dbase:"COUNTRY"`
-	WORKPHONE    string       `dbase:"WORKPHONE"`
-	NOTES        []uint8      `dbase:"NOTES"`
-}
-
-// Auto generated table struct: expense_categories 
-type EXPENSE_CATEGORIES struct {
-	EXPENSECAT   int32        `dbase:"EXPENSECAT"`
-	EXPENSECA2   string       `dbase:"EXPENSECA2"`
-	EXPENSECA3   int32        `dbase:"EXPENSECA3"`
-}
-
-// Auto generated table struct: expense_details 
-type EXPENSE_DETAILS struct {
-	EXPENSEDET   int32        `dbase:"EXPENSEDET"`
-	EXPENSEREP   int32        `dbase:"EXPENSEREP"`
-	EXPENSECAT   int32        `dbase:"EXPENSECAT"`
-	EXPENSEITE   float64      `dbase:"EXPENSEITE"`
-	EXPENSEIT2   string       `dbase:"EXPENSEIT2"`
-	EXPENSEDAT   time.Time    `dbase:"EXPENSEDAT"`
-}
-
-// Auto generated table struct: expense_reports 
-type EXPENSE_REPORTS struct {
-	EXPENSEREP   int32        `dbase:"EXPENSEREP"`
-	EMPLOYEEID   int32        `dbase:"EMPLOYEEID"`
-	EXPENSETYP   string       `dbase:"EXPENSETYP"`
-	EXPENSERPT   string       `dbase:"EXPENSERPT"`
-	EXPENSERP2   []uint8      `dbase:"EXPENSERP2"`
-	DATESUBMIT   time.Time    `dbase:"DATESUBMIT"`
-	ADVANCEAMO   float64      `dbase:"ADVANCEAMO"`
-	DEPARTMENT   string       `dbase:"DEPARTMENT"`
-	PAID         bool         `dbase:"PAID"`
+// Code generated by dbase-gen. DO NOT EDIT.
+// Source: EXPENSES.DBC
+
+package main
+
+import (
+	"time"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// Employees is the row struct of the table employees
+type Employees struct {
+	Employeeid int32  `dbase:"EMPLOYEEID,type=I"`
+	Department string `dbase:"DEPARTMENT,type=C,len=50"`
+	Socialsecu string `dbase:"SOCIALSECU,type=C,len=30"`
+	Employeenu string `dbase:"EMPLOYEENU,type=C,len=30"`
+	Firstname  string `dbase:"FIRSTNAME,type=C,len=50"`
+	Lastname   string `dbase:"LASTNAME,type=C,len=50"`
+	Title      string `dbase:"TITLE,type=C,len=50"`
+	Emailname  string `dbase:"EMAILNAME,type=C,len=50"`
+	Extension  string `dbase:"EXTENSION,type=C,len=30"`
+	Address    string `dbase:"ADDRESS,type=M"`
+	City       string `dbase:"CITY,type=C,len=50"`
+	Stateorpro string `dbase:"STATEORPRO,type=C,len=20"`
+	Postalcode string `dbase:"POSTALCODE,type=C,len=20"`
+	Country    string `dbase:"COUNTRY,type=C,len=50"`
+	Workphone  string `dbase:"WORKPHONE,type=C,len=30"`
+	Notes      string `dbase:"NOTES,type=M"`
 }
 
+// ScanEmployees converts the row to the Employees struct
+func ScanEmployees(row *dbase.Row) (*Employees, error) {
+	return dbase.Scan[*Employees](row)
+}
+
+// ReadEmployees reads all not deleted rows of the table as Employees structs
+func ReadEmployees(file *dbase.File) ([]*Employees, error) {
+	rows := make([]*Employees, 0, file.RowsCount())
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return nil, err
+		}
+		if row.Deleted {
+			continue
+		}
+		v, err := ScanEmployees(row)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, v)
+	}
+	return rows, nil
+}
+
+// Row converts the struct to a row of the table
+func (v *Employees) Row(file *dbase.File) (*dbase.Row, error) {
+	return file.RowFromStruct(v)
+}
+
+// ExpenseCategories is the row struct of the table expense_categories
+type ExpenseCategories struct {
+	Expensecat int32  `dbase:"EXPENSECAT,type=I"`
+	Expenseca2 string `dbase:"EXPENSECA2,type=C,len=50"`
+	Expenseca3 int32  `dbase:"EXPENSECA3,type=I"`
+}
+
+// ScanExpenseCategories converts the row to the ExpenseCategories struct
+func ScanExpenseCategories(row *dbase.Row) (*ExpenseCategories, error) {
+	return dbase.Scan[*ExpenseCategories](row)
+}
+
+// ReadExpenseCategories reads all not deleted rows of the table as ExpenseCategories structs
+func ReadExpenseCategories(file *dbase.File) ([]*ExpenseCategories, error) {
+	rows := make([]*ExpenseCategories, 0, file.RowsCount())
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return nil, err
+		}
+		if row.Deleted {
+			continue
+		}
+		v, err := ScanExpenseCategories(row)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, v)
+	}
+	return rows, nil
+}
+
+// Row converts the struct to a row of the table
+func (v *ExpenseCategories) Row(file *dbase.File) (*dbase.Row, error) {
+	return file.RowFromStruct(v)
+}
+
+// ExpenseDetails is the row struct of the table expense_details
+type ExpenseDetails struct {
+	Expensedet int32     `dbase:"EXPENSEDET,type=I"`
+	Expenserep int32     `dbase:"EXPENSEREP,type=I"`
+	Expensecat int32     `dbase:"EXPENSECAT,type=I"`
+	Expenseite float64   `dbase:"EXPENSEITE,type=Y,dec=4"`
+	Expenseit2 string    `dbase:"EXPENSEIT2,type=C,len=50"`
+	Expensedat time.Time `dbase:"EXPENSEDAT,type=T"`
+}
+
+// ScanExpenseDetails converts the row to the ExpenseDetails struct
+func ScanExpenseDetails(row *dbase.Row) (*ExpenseDetails, error) {
+	return dbase.Scan[*ExpenseDetails](row)
+}
+
+// ReadExpenseDetails reads all not deleted rows of the table as ExpenseDetails structs
+func ReadExpenseDetails(file *dbase.File) ([]*ExpenseDetails, error) {
+	rows := make([]*ExpenseDetails, 0, file.RowsCount())
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return nil, err
+		}
+		if row.Deleted {
+			continue
+		}
+		v, err := ScanExpenseDetails(row)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, v)
+	}
+	return rows, nil
+}
+
+// Row converts the struct to a row of the table
+func (v *ExpenseDetails) Row(file *dbase.File) (*dbase.Row, error) {
+	return file.RowFromStruct(v)
+}
+
+// ExpenseReports is the row struct of the table expense_reports
+type ExpenseReports struct {
+	Expenserep int32     `dbase:"EXPENSEREP,type=I"`
+	Employeeid int32     `dbase:"EMPLOYEEID,type=I"`
+	Expensetyp string    `dbase:"EXPENSETYP,type=C,len=50"`
+	Expenserpt string    `dbase:"EXPENSERPT,type=C,len=30"`
+	Expenserp2 string    `dbase:"EXPENSERP2,type=M"`
+	Datesubmit time.Time `dbase:"DATESUBMIT,type=T"`
+	Advanceamo float64   `dbase:"ADVANCEAMO,type=Y,dec=4"`
+	Department string    `dbase:"DEPARTMENT,type=C,len=30"`
+	Paid       bool      `dbase:"PAID,type=L"`
+}
+
+// ScanExpenseReports converts the row to the ExpenseReports struct
+func ScanExpenseReports(row *dbase.Row) (*ExpenseReports, error) {
+	return dbase.Scan[*ExpenseReports](row)
+}
+
+// ReadExpenseReports reads all not deleted rows of the table as ExpenseReports structs
+func ReadExpenseReports(file *dbase.File) ([]*ExpenseReports, error) {
+	rows := make([]*ExpenseReports, 0, file.RowsCount())
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return nil, err
+		}
+		if row.Deleted {
+			continue
+		}
+		v, err := ScanExpenseReports(row)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, v)
+	}
+	return rows, nil
+}
+
+// Row converts the struct to a row of the table
+func (v *ExpenseReports) Row(file *dbase.File) (*dbase.Row, error) {
+	return file.RowFromStruct(v)
+}
@@ -4,10 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"sort"
-	"strings"
 
 	"github.com/Valentin-Kaiser/go-dbase/dbase"
+	"github.com/Valentin-Kaiser/go-dbase/gen"
 )
 
 func main() {
@@ -27,11 +26,6 @@ func main() {
 	}
 	defer db.Close()
 
-	// Print the database schema
-	schema := db.Schema()
-	tables := db.Tables()
-	length := len(tables)
-
 	fmt.Println("Generating schema...")
 
 	// Open schema output file
@@ -39,63 +33,16 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	defer schemaFile.Close()
 
-	// Write file headline
-	_, err = schemaFile.WriteString("package main \n\n")
+	// Generate a struct with dbase tags and typed helpers for every table of the database.
+	// The same can be done with the dbase-gen command:
+	// go run github.com/Valentin-Kaiser/go-dbase/cmd/dbase-gen -db ../test_data/database/EXPENSES.DBC -out schema.gen.go
+	tables := gen.FromDatabase(db)
+	err = gen.Generate(schemaFile, tables, gen.Options{Package: "main", Source: "EXPENSES.DBC"})
 	if err != nil {
 		panic(err)
 	}
 
-	keys := make([]string, 0)
-	for table := range schema {
-		keys = append(keys, table)
-	}
-	sort.Strings(keys)
-
-	timeImport := false
-	tablesStructs := make([]string, 0)
-	for i, name := range keys {
-		tableStructSchema := fmt.Sprintf("// Auto generated table struct: %v \n", name)
-		tableStructSchema += fmt.Sprintf("type %v struct {\n", strings.ToUpper(name))
-
-		for _, column := range schema[name] {
-			if column.DataType == byte(dbase.Date) || column.DataType == byte(dbase.DateTime) {
-				timeImport = true
-			}
-			tableStructSchema += fmt.Sprintf("\t%-12.12s %-12.12s `dbase:\"%v\"`\n", column.Name(), column.Reflect(), column.Name())
-		}
-		tableStructSchema += "}\n\n"
-		tablesStructs = append(tablesStructs, tableStructSchema)
-
-		fmt.Printf("Generated %v/%v table schemas \n", i+1, length)
-	}
-
-	if timeImport {
-		_, err = schemaFile.WriteString("import \"time\"\n\n")
-		if err != nil {
-			panic(err)
-		}
-	}
-
-	for _, tableStruct := range tablesStructs {
-		_, err = schemaFile.WriteString(tableStruct)
-		if err != nil {
-			panic(err)
-		}
-	}
-}
-
-// ToByteString returns the number of bytes as a string with a unit
-func ToByteString(b int) string {
-	const unit = 1000
-	if b < unit {
-		return fmt.Sprintf("%d B", b)
-	}
-	div, exp := int64(unit), 0
-	for n := b / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f %cB",
-		float64(b)/float64(div), "kMGTPE"[exp])
+	fmt.Printf("Generated %v table schemas \n", len(tables))
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 
 	"github.com/Valentin-Kaiser/go-dbase/dbase"
 )
@@ -74,4 +75,32 @@ func main() {
 
 		fmt.Printf("%v \n", field.GetValue())
 	}
+
+	// Search for all products with a price higher than 20.
+	// Only the price column is interpreted before the predicate is called.
+	records, err = table.SearchFunc(func(row *dbase.Row) bool {
+		price, ok := row.FieldByName("PRICE").GetValue().(float64)
+		return ok && price > 20
+	}, "PRICE")
+	if err != nil {
+		panic(dbase.GetErrorTrace(err))
+	}
+
+	// Print all found records.
+	fmt.Println("Found records with a price higher than 20:")
+	for _, record := range records {
+		fmt.Printf("%v \n", record.FieldByName("PRODNAME").GetValue())
+	}
+
+	// Search for all products whose name starts with "TEST" followed by a space or underscore.
+	records, err = table.SearchRegex("PRODNAME", regexp.MustCompile(`^(?i)test[ _]`))
+	if err != nil {
+		panic(dbase.GetErrorTrace(err))
+	}
+
+	// Print all found records.
+	fmt.Println("Found records matching the regular expression:")
+	for _, record := range records {
+		fmt.Printf("%v \n", record.FieldByName("PRODNAME").GetValue())
+	}
 }
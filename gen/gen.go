@@ -0,0 +1,239 @@
+// Package gen generates Go structs and typed helpers for dBase tables.
+// The generated structs use the dbase tags understood by Scan, RowFromStruct, ToStruct, NewFromStruct and ValidateStruct.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// Table is the name and the columns of a table to generate code for
+type Table struct {
+	Name    string          // Table name, used to derive the struct name
+	Columns []*dbase.Column // Columns of the table
+}
+
+// Options configures the generated code
+type Options struct {
+	Package string // Package name of the generated file, "main" if empty
+	Source  string // Source of the schema mentioned in the file header
+}
+
+// FromDatabase returns the tables of an opened database sorted by name
+func FromDatabase(db *dbase.Database) []Table {
+	tables := make([]Table, 0)
+	for name, columns := range db.Schema() {
+		tables = append(tables, Table{Name: name, Columns: columns})
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return tables
+}
+
+// FromDirectory opens every DBF file in the directory and returns the tables sorted by name.
+// The config is used as template to open the tables, the filename is replaced.
+func FromDirectory(dir string, config dbase.Config) ([]Table, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %v failed with error: %w", dir, err)
+	}
+	tables := make([]Table, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.ToUpper(filepath.Ext(entry.Name())) != string(dbase.DBF) {
+			continue
+		}
+		c := config
+		c.Filename = filepath.Join(dir, entry.Name())
+		file, err := dbase.OpenTable(&c)
+		if err != nil {
+			return nil, fmt.Errorf("opening table %v failed with error: %w", entry.Name(), err)
+		}
+		tables = append(tables, Table{
+			Name:    strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			Columns: file.Columns(),
+		})
+		err = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("closing table %v failed with error: %w", entry.Name(), err)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return tables, nil
+}
+
+// Generate writes a formatted Go file with a struct and typed helpers for every table
+func Generate(w io.Writer, tables []Table, opts Options) error {
+	pkg := opts.Package
+	if len(pkg) == 0 {
+		pkg = "main"
+	}
+	imports := make(map[string]bool)
+	declared := make(map[string]bool)
+	body := new(bytes.Buffer)
+	for _, table := range tables {
+		err := generateTable(body, table, imports, declared)
+		if err != nil {
+			return err
+		}
+	}
+	out := new(bytes.Buffer)
+	fmt.Fprintf(out, "// Code generated by dbase-gen. DO NOT EDIT.\n")
+	if len(opts.Source) > 0 {
+		fmt.Fprintf(out, "// Source: %v\n", opts.Source)
+	}
+	fmt.Fprintf(out, "\npackage %v\n\n", pkg)
+	fmt.Fprintf(out, "import (\n")
+	if imports["time"] {
+		fmt.Fprintf(out, "\t\"time\"\n\n")
+	}
+	fmt.Fprintf(out, "\t\"github.com/Valentin-Kaiser/go-dbase/dbase\"\n)\n\n")
+	out.Write(body.Bytes())
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated code failed with error: %w", err)
+	}
+	_, err = w.Write(src)
+	if err != nil {
+		return fmt.Errorf("writing generated code failed with error: %w", err)
+	}
+	return nil
+}
+
+// Writes the struct and helpers of one table.
+// Declared contains the package level names of the previous tables, table names that map to the same Go name get a suffix.
+func generateTable(w io.Writer, table Table, imports map[string]bool, declared map[string]bool) error {
+	name := GoName(table.Name)
+	if len(name) == 0 {
+		return fmt.Errorf("invalid table name %q", table.Name)
+	}
+	for declared[name] || declared["Scan"+name] || declared["Read"+name] {
+		name += "_"
+	}
+	declared[name] = true
+	declared["Scan"+name] = true
+	declared["Read"+name] = true
+	fmt.Fprintf(w, "// %v is the row struct of the table %v\n", name, table.Name)
+	fmt.Fprintf(w, "type %v struct {\n", name)
+	// The Row method can not have the name of a field
+	fields := map[string]bool{"Row": true}
+	for _, column := range table.Columns {
+		goType, err := GoType(column)
+		if err != nil {
+			return fmt.Errorf("table %v: %w", table.Name, err)
+		}
+		if strings.Contains(goType, "time.") {
+			imports["time"] = true
+		}
+		field := GoName(column.Name())
+		if len(field) == 0 {
+			field = "Column"
+		}
+		// Column names can differ only in underscores or be named like the Row method
+		for fields[field] {
+			field += "_"
+		}
+		fields[field] = true
+		fmt.Fprintf(w, "\t%v %v `dbase:\"%v\"`\n", field, goType, Tag(column))
+	}
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "// Scan%[1]v converts the row to the %[1]v struct\n", name)
+	fmt.Fprintf(w, "func Scan%[1]v(row *dbase.Row) (*%[1]v, error) {\n", name)
+	fmt.Fprintf(w, "\treturn dbase.Scan[*%v](row)\n", name)
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "// Read%[1]v reads all not deleted rows of the table as %[1]v structs\n", name)
+	fmt.Fprintf(w, "func Read%[1]v(file *dbase.File) ([]*%[1]v, error) {\n", name)
+	fmt.Fprintf(w, "\trows := make([]*%v, 0, file.RowsCount())\n", name)
+	fmt.Fprintf(w, "\tcursor := file.NewCursor()\n")
+	fmt.Fprintf(w, "\tfor !cursor.EOF() {\n")
+	fmt.Fprintf(w, "\t\trow, err := cursor.Next()\n")
+	fmt.Fprintf(w, "\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n")
+	fmt.Fprintf(w, "\t\tif row.Deleted {\n\t\t\tcontinue\n\t\t}\n")
+	fmt.Fprintf(w, "\t\tv, err := Scan%v(row)\n", name)
+	fmt.Fprintf(w, "\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n")
+	fmt.Fprintf(w, "\t\trows = append(rows, v)\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\treturn rows, nil\n")
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "// Row converts the struct to a row of the table\n")
+	fmt.Fprintf(w, "func (v *%v) Row(file *dbase.File) (*dbase.Row, error) {\n", name)
+	fmt.Fprintf(w, "\treturn file.RowFromStruct(v)\n")
+	fmt.Fprintf(w, "}\n\n")
+	return nil
+}
+
+// GoType returns the Go type of the column values, nullable columns are pointers
+func GoType(column *dbase.Column) (string, error) {
+	binary := column.Flag&byte(dbase.BinaryFlag) != 0
+	var goType string
+	switch dbase.DataType(column.DataType) {
+	case dbase.Character:
+		goType = "string"
+	case dbase.Varchar, dbase.Memo:
+		goType = "string"
+		if binary {
+			goType = "[]byte"
+		}
+	case dbase.Varbinary, dbase.Blob, dbase.General, dbase.Picture, dbase.NullFlags:
+		goType = "[]byte"
+	case dbase.Numeric:
+		goType = "int64"
+		if column.Decimals > 0 {
+			goType = "float64"
+		}
+	case dbase.Float, dbase.Double, dbase.DBaseDouble, dbase.Currency:
+		goType = "float64"
+	case dbase.Integer, dbase.Autoincrement:
+		goType = "int32"
+	case dbase.Logical:
+		goType = "bool"
+	case dbase.Date, dbase.DateTime, dbase.Timestamp:
+		goType = "time.Time"
+	default:
+		return "", fmt.Errorf("unsupported column data type %v of column %v", column.Type(), column.Name())
+	}
+	if column.Flag&byte(dbase.NullableFlag) != 0 && !strings.HasPrefix(goType, "[]") {
+		goType = "*" + goType
+	}
+	return goType, nil
+}
+
+// Tag returns the dbase tag describing the column
+func Tag(column *dbase.Column) string {
+	tag := fmt.Sprintf("%v,type=%v", column.Name(), column.Type())
+	switch dbase.DataType(column.DataType) {
+	case dbase.Character, dbase.Varchar, dbase.Varbinary, dbase.Numeric, dbase.Float:
+		tag += fmt.Sprintf(",len=%v", column.Length)
+	}
+	if column.Decimals > 0 {
+		tag += fmt.Sprintf(",dec=%v", column.Decimals)
+	}
+	if column.Flag&byte(dbase.NullableFlag) != 0 {
+		tag += ",nullable"
+	}
+	return tag
+}
+
+// GoName converts a table or column name (e.g. EXPENSE_DETAILS) to an exported Go identifier (ExpenseDetails)
+func GoName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := ""
+	for _, part := range parts {
+		runes := []rune(strings.ToLower(part))
+		runes[0] = unicode.ToUpper(runes[0])
+		out += string(runes)
+	}
+	if len(out) > 0 && !unicode.IsLetter([]rune(out)[0]) {
+		out = "T" + out
+	}
+	return out
+}
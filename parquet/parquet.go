@@ -0,0 +1,598 @@
+// Package parquet exports dBase tables as Apache Parquet files.
+// The Parquet schema is derived from the column types and the rows are written in row groups,
+// so the memory usage is bounded by the row group size and not by the size of the table.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+)
+
+// Parquet physical types
+const (
+	typeBoolean           = 0
+	typeInt32             = 1
+	typeInt64             = 2
+	typeDouble            = 5
+	typeByteArray         = 6
+	typeFixedLenByteArray = 7
+)
+
+// Parquet converted types, written in addition to the logical types for older readers
+const (
+	convertedNone            = -1
+	convertedUTF8            = 0
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimestampMillis = 9
+)
+
+// Parquet logical types (field ids of the LogicalType union)
+const (
+	logicalNone      = 0
+	logicalString    = 1
+	logicalDecimal   = 5
+	logicalDate      = 6
+	logicalTimestamp = 8
+)
+
+// Parquet encodings
+const (
+	encodingPlain = 0
+	encodingRLE   = 3
+)
+
+var magic = []byte("PAR1")
+
+// Options configures the Parquet export
+type Options struct {
+	RowGroupSize  int // Maximum number of rows of a row group, 10000 if 0
+	RowGroupBytes int // Maximum size of the buffered values of a row group, 64 MiB if 0
+}
+
+// A table column with its Parquet schema and the buffered values of the current row group
+type column struct {
+	source    *dbase.Column
+	position  int   // Position of the column in the row
+	physical  int32 // Physical type
+	length    int32 // Length of FIXED_LEN_BYTE_ARRAY values
+	converted int32 // Converted type, convertedNone if none
+	logical   int16 // Logical type, logicalNone if none
+	scale     int32 // Scale of decimals
+	precision int32 // Precision of decimals
+	optional  bool  // Values may be null
+
+	levels []byte       // Definition levels of the buffered values, 0 for null
+	values bytes.Buffer // PLAIN encoded non-null values
+	bools  []bool       // Non-null BOOLEAN values, bit-packed when the page is written
+}
+
+// A written column chunk
+type chunk struct {
+	offset int64 // Offset of the data page
+	size   int64 // Size of the page header and page
+	values int64 // Number of values including nulls
+}
+
+// A written row group
+type rowGroup struct {
+	chunks []chunk
+	rows   int64
+	size   int64
+}
+
+// Writes the Parquet file
+type exporter struct {
+	w       io.Writer
+	offset  int64
+	columns []*column
+	groups  []rowGroup
+	rows    int   // Buffered rows of the current row group
+	total   int64 // Written rows
+	opts    Options
+}
+
+// Export writes the not deleted rows of the table as Parquet file and returns the number of written rows.
+// Numeric and Currency columns are written as decimal, Date columns as date and DateTime and Timestamp columns as
+// timestamp (milliseconds, UTC). Integer columns are written as INT32, Float and Double columns as DOUBLE, Logical columns
+// as BOOLEAN, text as UTF-8 strings and binary data (BinaryFlag, Varbinary, Blob, General and Picture) as BYTE_ARRAY.
+// Columns with NullableFlag are optional, as are Date, DateTime and Timestamp columns whose empty values are written as null.
+// All columns of dBase level 7 tables are optional, as are Numeric and Float columns of tables other than Visual FoxPro tables
+// whose overflow values (asterisks) are written as null. Character values are written without trailing spaces.
+func Export(file *dbase.File, w io.Writer, opts Options) (int, error) {
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = 10000
+	}
+	if opts.RowGroupBytes <= 0 {
+		opts.RowGroupBytes = 64 << 20
+	}
+	header := file.Header()
+	e := &exporter{w: w, opts: opts}
+	for i, c := range file.Columns() {
+		if dbase.DataType(c.DataType) == dbase.NullFlags {
+			continue
+		}
+		col, err := newColumn(c, i, header)
+		if err != nil {
+			return 0, err
+		}
+		e.columns = append(e.columns, col)
+	}
+	err := e.write(magic)
+	if err != nil {
+		return 0, err
+	}
+	cursor := file.NewCursor()
+	for !cursor.EOF() {
+		row, err := cursor.Next()
+		if err != nil {
+			return int(e.total), fmt.Errorf("reading row failed with error: %w", err)
+		}
+		if row.Deleted {
+			continue
+		}
+		for _, col := range e.columns {
+			field := row.Field(col.position)
+			value, err := field.Value()
+			if err != nil {
+				return int(e.total), fmt.Errorf("interpreting column %v of row %v failed with error: %w", col.source.Name(), row.Position, err)
+			}
+			err = col.append(value)
+			if err != nil {
+				return int(e.total), fmt.Errorf("column %v of row %v: %w", col.source.Name(), row.Position, err)
+			}
+		}
+		e.rows++
+		if e.rows >= opts.RowGroupSize || e.buffered() >= opts.RowGroupBytes {
+			err = e.flush()
+			if err != nil {
+				return int(e.total), err
+			}
+		}
+	}
+	if e.rows > 0 {
+		err = e.flush()
+		if err != nil {
+			return int(e.total), err
+		}
+	}
+	metadata := e.metadata()
+	footer := make([]byte, 4, 8)
+	binary.LittleEndian.PutUint32(footer, uint32(len(metadata)))
+	footer = append(footer, magic...)
+	err = e.write(metadata)
+	if err != nil {
+		return int(e.total), err
+	}
+	err = e.write(footer)
+	if err != nil {
+		return int(e.total), err
+	}
+	return int(e.total), nil
+}
+
+// ExportDatabase writes every table of the database as <table name>.parquet file into the directory
+func ExportDatabase(db *dbase.Database, dir string, opts Options) error {
+	names := db.Names()
+	sort.Strings(names)
+	tables := db.Tables()
+	for _, name := range names {
+		path := filepath.Join(dir, name+".parquet")
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("creating file %v failed with error: %w", path, err)
+		}
+		_, err = Export(tables[name], f, opts)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("exporting table %v failed with error: %w", name, err)
+		}
+		err = f.Close()
+		if err != nil {
+			return fmt.Errorf("closing file %v failed with error: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Returns if the table is a Visual FoxPro table, other tables can contain N and F overflow values (asterisks)
+func visualFoxPro(header *dbase.Header) bool {
+	switch dbase.FileVersion(header.FileType) {
+	case dbase.FoxPro, dbase.FoxProAutoincrement, dbase.FoxProVar:
+		return true
+	}
+	return false
+}
+
+// Derives the Parquet schema of the column
+func newColumn(c *dbase.Column, position int, header *dbase.Header) (*column, error) {
+	col := &column{
+		source:    c,
+		position:  position,
+		converted: convertedNone,
+		optional:  header.Level7() || c.Flag&byte(dbase.NullableFlag) != 0,
+	}
+	if dbase.DataType(c.DataType) == dbase.Numeric || dbase.DataType(c.DataType) == dbase.Float {
+		col.optional = col.optional || !visualFoxPro(header)
+	}
+	binaryFlag := c.Flag&byte(dbase.BinaryFlag) != 0
+	switch dbase.DataType(c.DataType) {
+	case dbase.Character, dbase.Varchar, dbase.Memo:
+		col.physical = typeByteArray
+		if !binaryFlag {
+			col.converted = convertedUTF8
+			col.logical = logicalString
+		}
+	case dbase.Varbinary, dbase.Blob, dbase.General, dbase.Picture:
+		col.physical = typeByteArray
+	case dbase.Integer, dbase.Autoincrement:
+		col.physical = typeInt32
+	case dbase.Numeric:
+		precision := int32(c.Length)
+		if c.Decimals > 0 {
+			// The decimal point takes one character
+			precision--
+		}
+		col.decimal(precision, int32(c.Decimals))
+	case dbase.Currency:
+		col.decimal(19, 4)
+	case dbase.Float, dbase.Double, dbase.DBaseDouble:
+		col.physical = typeDouble
+	case dbase.Logical:
+		col.physical = typeBoolean
+	case dbase.Date:
+		col.physical = typeInt32
+		col.converted = convertedDate
+		col.logical = logicalDate
+		col.optional = true
+	case dbase.DateTime, dbase.Timestamp:
+		col.physical = typeInt64
+		col.converted = convertedTimestampMillis
+		col.logical = logicalTimestamp
+		col.optional = true
+	default:
+		return nil, fmt.Errorf("unsupported data type %v of column %v", c.Type(), c.Name())
+	}
+	return col, nil
+}
+
+// Sets the decimal type, stored as INT64 up to 18 digits and as FIXED_LEN_BYTE_ARRAY above
+func (col *column) decimal(precision, scale int32) {
+	if precision < scale {
+		precision = scale
+	}
+	if precision < 1 {
+		precision = 1
+	}
+	col.precision = precision
+	col.scale = scale
+	col.converted = convertedDecimal
+	col.logical = logicalDecimal
+	col.physical = typeInt64
+	if precision > 18 {
+		col.physical = typeFixedLenByteArray
+		// Smallest two's complement length holding 10^precision - 1
+		max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+		col.length = int32(max.BitLen()/8 + 1)
+	}
+}
+
+// Buffers the value of the current row
+func (col *column) append(value interface{}) error {
+	if t, ok := value.(time.Time); ok && t.IsZero() {
+		value = nil
+	}
+	if value == nil {
+		if !col.optional {
+			return fmt.Errorf("null value in required column")
+		}
+		col.levels = append(col.levels, 0)
+		return nil
+	}
+	col.levels = append(col.levels, 1)
+	var err error
+	switch col.logical {
+	case logicalDecimal:
+		err = col.appendDecimal(value)
+	case logicalDate:
+		t, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected value type %T", value)
+		}
+		days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+		err = binary.Write(&col.values, binary.LittleEndian, int32(days))
+	case logicalTimestamp:
+		t, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected value type %T", value)
+		}
+		err = binary.Write(&col.values, binary.LittleEndian, t.UnixMilli())
+	default:
+		err = col.appendPlain(value)
+	}
+	return err
+}
+
+// Buffers values without logical type or strings
+func (col *column) appendPlain(value interface{}) error {
+	switch col.physical {
+	case typeByteArray:
+		var data []byte
+		switch v := value.(type) {
+		case string:
+			if dbase.DataType(col.source.DataType) == dbase.Character {
+				v = strings.TrimRight(v, " ")
+			}
+			data = []byte(v)
+		case []byte:
+			data = v
+		default:
+			return fmt.Errorf("unexpected value type %T", value)
+		}
+		var length [4]byte
+		binary.LittleEndian.PutUint32(length[:], uint32(len(data)))
+		col.values.Write(length[:])
+		col.values.Write(data)
+	case typeInt32:
+		var i int64
+		switch v := value.(type) {
+		case int32:
+			i = int64(v)
+		case int64:
+			i = v
+		case int:
+			i = int64(v)
+		default:
+			return fmt.Errorf("unexpected value type %T", value)
+		}
+		if i < math.MinInt32 || i > math.MaxInt32 {
+			return fmt.Errorf("value %v exceeds INT32", i)
+		}
+		return binary.Write(&col.values, binary.LittleEndian, int32(i))
+	case typeDouble:
+		var f float64
+		switch v := value.(type) {
+		case float64:
+			f = v
+		case dbase.Decimal:
+			f = v.Float64()
+		case int32:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		default:
+			return fmt.Errorf("unexpected value type %T", value)
+		}
+		return binary.Write(&col.values, binary.LittleEndian, math.Float64bits(f))
+	case typeBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected value type %T", value)
+		}
+		col.bools = append(col.bools, b)
+	}
+	return nil
+}
+
+// Buffers the unscaled value of a decimal
+func (col *column) appendDecimal(value interface{}) error {
+	var unscaled *big.Int
+	switch v := value.(type) {
+	case int32:
+		unscaled = big.NewInt(int64(v))
+		unscaled.Mul(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(col.scale)), nil))
+	case int64:
+		unscaled = big.NewInt(v)
+		unscaled.Mul(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(col.scale)), nil))
+	case float64:
+		d, err := dbase.DecimalFromFloat(v, uint8(col.scale))
+		if err != nil {
+			return err
+		}
+		unscaled = d.Unscaled()
+	case dbase.Decimal:
+		unscaled = v.Round(uint8(col.scale)).Unscaled()
+	default:
+		return fmt.Errorf("unexpected value type %T", value)
+	}
+	if col.physical == typeInt64 {
+		if !unscaled.IsInt64() {
+			return fmt.Errorf("value %v exceeds the decimal precision %v", value, col.precision)
+		}
+		return binary.Write(&col.values, binary.LittleEndian, unscaled.Int64())
+	}
+	// Big-endian two's complement
+	if unscaled.BitLen() >= int(col.length)*8 {
+		return fmt.Errorf("value %v exceeds the decimal precision %v", value, col.precision)
+	}
+	if unscaled.Sign() < 0 {
+		unscaled.Add(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(col.length)*8))
+	}
+	col.values.Write(unscaled.FillBytes(make([]byte, col.length)))
+	return nil
+}
+
+// Returns the encoded data page of the buffered values
+func (col *column) page() []byte {
+	var page bytes.Buffer
+	if col.optional {
+		// Definition levels as RLE runs with bit width 1, prefixed with their length
+		var levels bytes.Buffer
+		var header [binary.MaxVarintLen64]byte
+		for i := 0; i < len(col.levels); {
+			j := i
+			for j < len(col.levels) && col.levels[j] == col.levels[i] {
+				j++
+			}
+			n := binary.PutUvarint(header[:], uint64(j-i)<<1)
+			levels.Write(header[:n])
+			levels.WriteByte(col.levels[i])
+			i = j
+		}
+		var length [4]byte
+		binary.LittleEndian.PutUint32(length[:], uint32(levels.Len()))
+		page.Write(length[:])
+		page.Write(levels.Bytes())
+	}
+	if col.physical == typeBoolean {
+		packed := make([]byte, (len(col.bools)+7)/8)
+		for i, b := range col.bools {
+			if b {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		page.Write(packed)
+	}
+	page.Write(col.values.Bytes())
+	return page.Bytes()
+}
+
+// Returns the size of the buffered values
+func (e *exporter) buffered() int {
+	size := 0
+	for _, col := range e.columns {
+		size += col.values.Len() + len(col.bools)/8 + len(col.levels)
+	}
+	return size
+}
+
+// Writes the buffered rows as row group
+func (e *exporter) flush() error {
+	group := rowGroup{rows: int64(e.rows), chunks: make([]chunk, 0, len(e.columns))}
+	for _, col := range e.columns {
+		page := col.page()
+		header := pageHeader(len(page), len(col.levels))
+		c := chunk{offset: e.offset, size: int64(len(header) + len(page)), values: int64(len(col.levels))}
+		err := e.write(header)
+		if err != nil {
+			return err
+		}
+		err = e.write(page)
+		if err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, c)
+		group.size += c.size
+		col.levels = col.levels[:0]
+		col.bools = col.bools[:0]
+		col.values.Reset()
+	}
+	e.groups = append(e.groups, group)
+	e.total += int64(e.rows)
+	e.rows = 0
+	return nil
+}
+
+func (e *exporter) write(data []byte) error {
+	n, err := e.w.Write(data)
+	e.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("writing parquet data failed with error: %w", err)
+	}
+	return nil
+}
+
+// Returns the PageHeader of an uncompressed PLAIN encoded data page
+func pageHeader(size int, values int) []byte {
+	t := newThriftWriter()
+	t.i32(1, 0) // DATA_PAGE
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	t.begin(5)
+	t.i32(1, int32(values))
+	t.i32(2, encodingPlain)
+	t.i32(3, encodingRLE)
+	t.i32(4, encodingRLE)
+	t.end()
+	return t.bytes()
+}
+
+// Returns the FileMetaData with the schema and the written row groups
+func (e *exporter) metadata() []byte {
+	t := newThriftWriter()
+	t.i32(1, 1)
+	t.list(2, thriftStruct, len(e.columns)+1)
+	t.begin(0)
+	t.string(4, "schema")
+	t.i32(5, int32(len(e.columns)))
+	t.end()
+	for _, col := range e.columns {
+		t.begin(0)
+		t.i32(1, col.physical)
+		if col.physical == typeFixedLenByteArray {
+			t.i32(2, col.length)
+		}
+		if col.optional {
+			t.i32(3, 1)
+		} else {
+			t.i32(3, 0)
+		}
+		t.string(4, col.source.Name())
+		if col.converted != convertedNone {
+			t.i32(6, col.converted)
+		}
+		if col.logical == logicalDecimal {
+			t.i32(7, col.scale)
+			t.i32(8, col.precision)
+		}
+		if col.logical != logicalNone {
+			t.begin(10)
+			t.begin(col.logical)
+			switch col.logical {
+			case logicalDecimal:
+				t.i32(1, col.scale)
+				t.i32(2, col.precision)
+			case logicalTimestamp:
+				t.bool(1, true)
+				t.begin(2)
+				t.begin(1) // MILLIS
+				t.end()
+				t.end()
+			}
+			t.end()
+			t.end()
+		}
+		t.end()
+	}
+	t.i64(3, e.total)
+	t.list(4, thriftStruct, len(e.groups))
+	for _, group := range e.groups {
+		t.begin(0)
+		t.list(1, thriftStruct, len(group.chunks))
+		for i, c := range group.chunks {
+			col := e.columns[i]
+			t.begin(0)
+			t.i64(2, c.offset)
+			t.begin(3)
+			t.i32(1, col.physical)
+			t.list(2, thriftI32, 2)
+			t.i32Element(encodingPlain)
+			t.i32Element(encodingRLE)
+			t.list(3, thriftBinary, 1)
+			t.stringElement(col.source.Name())
+			t.i32(4, 0) // UNCOMPRESSED
+			t.i64(5, c.values)
+			t.i64(6, c.size)
+			t.i64(7, c.size)
+			t.i64(9, c.offset)
+			t.end()
+			t.end()
+		}
+		t.i64(2, group.size)
+		t.i64(3, group.rows)
+		t.end()
+	}
+	t.string(6, "go-dbase")
+	return t.bytes()
+}
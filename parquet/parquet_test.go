@@ -0,0 +1,276 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Valentin-Kaiser/go-dbase/dbase"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Decodes the Thrift compact protocol structs of the file metadata and page headers, fields by id
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case thriftTrue:
+		return true
+	case thriftFalse:
+		return false
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		n := int(r.varint())
+		r.pos += n
+		return string(r.data[r.pos-n : r.pos])
+	case thriftList:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return r.object()
+	}
+	panic(fmt.Sprintf("unexpected thrift type %v", typ))
+}
+
+func (r *thriftReader) object() map[int64]interface{} {
+	fields := make(map[int64]interface{})
+	id := int64(0)
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		if delta := int64(header >> 4); delta > 0 {
+			id += delta
+		} else {
+			id = r.zigzag()
+		}
+		fields[id] = r.value(header & 0x0f)
+	}
+}
+
+// Reads the schema and the values of all row groups, nulls are nil
+func readParquet(t *testing.T, data []byte) (map[string]bool, map[string][]interface{}, int64) {
+	if !bytes.HasPrefix(data, magic) || !bytes.HasSuffix(data, magic) {
+		t.Fatal("missing parquet magic")
+	}
+	length := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	metadata := (&thriftReader{data: data[len(data)-8-length : len(data)-8]}).object()
+	schema := metadata[2].([]interface{})[1:]
+	optional := make(map[string]bool)
+	values := make(map[string][]interface{})
+	for _, group := range metadata[4].([]interface{}) {
+		for i, c := range group.(map[int64]interface{})[1].([]interface{}) {
+			element := schema[i].(map[int64]interface{})
+			name := element[4].(string)
+			optional[name] = element[3].(int64) == 1
+			r := &thriftReader{data: data, pos: int(c.(map[int64]interface{})[2].(int64))}
+			header := r.object()
+			count := int(header[5].(map[int64]interface{})[1].(int64))
+			page := data[r.pos : r.pos+int(header[3].(int64))]
+			levels := bytes.Repeat([]byte{1}, count)
+			if optional[name] {
+				size := int(binary.LittleEndian.Uint32(page))
+				runs := &thriftReader{data: page[4 : 4+size]}
+				for k := 0; runs.pos < size; {
+					n := int(runs.varint() >> 1)
+					level := runs.data[runs.pos]
+					runs.pos++
+					for ; n > 0; n-- {
+						levels[k] = level
+						k++
+					}
+				}
+				page = page[4+size:]
+			}
+			bit := 0
+			for _, level := range levels {
+				if level == 0 {
+					values[name] = append(values[name], nil)
+					continue
+				}
+				var value interface{}
+				switch element[1].(int64) {
+				case typeBoolean:
+					value = page[bit/8]>>(bit%8)&1 == 1
+					bit++
+				case typeInt32:
+					value = int32(binary.LittleEndian.Uint32(page))
+					page = page[4:]
+				case typeInt64:
+					value = int64(binary.LittleEndian.Uint64(page))
+					page = page[8:]
+				case typeDouble:
+					value = math.Float64frombits(binary.LittleEndian.Uint64(page))
+					page = page[8:]
+				case typeByteArray:
+					n := binary.LittleEndian.Uint32(page)
+					value = string(page[4 : 4+n])
+					page = page[4+n:]
+				}
+				values[name] = append(values[name], value)
+			}
+		}
+	}
+	return optional, values, metadata[3].(int64)
+}
+
+// Creates a table with the rows, overflow patches the raw data of a row and column with asterisks
+func createTable(t *testing.T, version dbase.FileVersion, rows []map[string]interface{}, overflow map[uint32][]string) *dbase.File {
+	// New creates the file with an upper case path, the table is created in the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	path := "TEST.DBF"
+	config := &dbase.Config{Filename: path, Converter: dbase.NewDefaultConverter(charmap.Windows1252), Untested: true}
+	columns := make([]*dbase.Column, 0)
+	for _, c := range []struct {
+		name     string
+		dataType dbase.DataType
+		length   uint8
+		decimals uint8
+	}{
+		{"NAME", dbase.Character, 10, 0},
+		{"QTY", dbase.Numeric, 5, 0},
+		{"PRICE", dbase.Numeric, 8, 2},
+		{"RATE", dbase.Float, 6, 2},
+		{"OK", dbase.Logical, 1, 0},
+		{"BORN", dbase.Date, 8, 0},
+	} {
+		column, err := dbase.NewColumn(c.name, c.dataType, c.length, c.decimals, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, column)
+	}
+	file, err := dbase.New(version, config, columns, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, values := range rows {
+		row, err := file.RowFromMap(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = row.Add()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	header := *file.Header()
+	positions := make(map[string]uint32)
+	for _, column := range file.Columns() {
+		positions[column.Name()] = column.Position
+	}
+	err = file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for position, names := range overflow {
+		for _, name := range names {
+			offset := int(header.FirstRow) + int(position)*int(header.RowLength) + int(positions[name])
+			column := columns[file.ColumnPosByName(name)]
+			copy(data[offset:], bytes.Repeat([]byte{'*'}, int(column.Length)))
+		}
+	}
+	err = os.WriteFile(path, data, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err = dbase.OpenTable(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestExport(t *testing.T) {
+	born := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	rows := []map[string]interface{}{
+		{"NAME": "Müller", "QTY": int64(3), "PRICE": 12.5, "RATE": 1.25, "OK": true, "BORN": born},
+		{"NAME": "x", "QTY": int64(7), "PRICE": 0.5, "RATE": 2.5, "OK": false},
+	}
+	file := createTable(t, dbase.FoxBasePlus, rows, map[uint32][]string{1: {"QTY", "RATE"}})
+	buf := new(bytes.Buffer)
+	n, err := Export(file, buf, Options{RowGroupSize: 1})
+	if err != nil {
+		t.Fatalf("export failed with error: %v", err)
+	}
+	optional, values, total := readParquet(t, buf.Bytes())
+	if n != 2 || total != 2 {
+		t.Fatalf("exported %v rows (metadata %v), expected 2", n, total)
+	}
+	expected := map[string][]interface{}{
+		"NAME":  {"Müller", "x"},
+		"QTY":   {int64(3), nil},
+		"PRICE": {int64(1250), int64(50)},
+		"RATE":  {1.25, nil},
+		"OK":    {true, false},
+		"BORN":  {int32(born.Unix() / 86400), nil},
+	}
+	for name, want := range expected {
+		if !reflect.DeepEqual(values[name], want) {
+			t.Errorf("column %v has values %v, expected %v", name, values[name], want)
+		}
+	}
+	for name, want := range map[string]bool{"NAME": false, "QTY": true, "PRICE": true, "RATE": true, "OK": false, "BORN": true} {
+		if optional[name] != want {
+			t.Errorf("column %v optional = %v, expected %v", name, optional[name], want)
+		}
+	}
+}
+
+func TestExportVisualFoxPro(t *testing.T) {
+	rows := []map[string]interface{}{{"NAME": "a", "QTY": int64(1), "PRICE": 1.0, "RATE": 1.0}}
+	file := createTable(t, dbase.FoxPro, rows, nil)
+	buf := new(bytes.Buffer)
+	_, err := Export(file, buf, Options{})
+	if err != nil {
+		t.Fatalf("export failed with error: %v", err)
+	}
+	optional, _, _ := readParquet(t, buf.Bytes())
+	if optional["QTY"] || optional["RATE"] {
+		t.Error("N and F columns of Visual FoxPro tables have to be required")
+	}
+}